@@ -27,6 +27,7 @@ type Interface interface {
 	UsersInterface
 	UserIdentityMappingsInterface
 	ProjectsInterface
+	OAuthClientsInterface
 	PoliciesNamespacer
 	RolesNamespacer
 	RoleBindingsNamespacer
@@ -93,6 +94,11 @@ func (c *Client) Projects() ProjectInterface {
 	return newProjects(c)
 }
 
+// OAuthClients provides a REST client for OAuthClient
+func (c *Client) OAuthClients() OAuthClientInterface {
+	return newOAuthClients(c)
+}
+
 // TemplateConfigs provides a REST client for TemplateConfig
 func (c *Client) TemplateConfigs(namespace string) TemplateConfigInterface {
 	return newTemplateConfigs(c, namespace)
@@ -64,6 +64,10 @@ func (c *Fake) Projects() ProjectInterface {
 	return &FakeProjects{Fake: c}
 }
 
+func (c *Fake) OAuthClients() OAuthClientInterface {
+	return &FakeOAuthClients{Fake: c}
+}
+
 func (c *Fake) Policies(namespace string) PolicyInterface {
 	return &FakePolicies{Fake: c}
 }
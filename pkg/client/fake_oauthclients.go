@@ -0,0 +1,16 @@
+package client
+
+import (
+	oauthapi "github.com/openshift/origin/pkg/oauth/api"
+)
+
+// FakeOAuthClients implements OAuthClientInterface. Meant to be embedded into a struct to get a default
+// implementation. This makes faking out just the methods you want to test easier.
+type FakeOAuthClients struct {
+	Fake *Fake
+}
+
+func (c *FakeOAuthClients) Get(name string) (*oauthapi.OAuthClient, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "get-oauthclient", Value: name})
+	return &oauthapi.OAuthClient{}, nil
+}
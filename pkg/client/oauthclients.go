@@ -0,0 +1,35 @@
+package client
+
+import (
+	oauthapi "github.com/openshift/origin/pkg/oauth/api"
+	_ "github.com/openshift/origin/pkg/oauth/api/v1beta1"
+)
+
+// OAuthClientsInterface has methods to work with OAuthClient resources
+type OAuthClientsInterface interface {
+	OAuthClients() OAuthClientInterface
+}
+
+// OAuthClientInterface exposes methods on OAuthClient resources.
+type OAuthClientInterface interface {
+	Get(name string) (*oauthapi.OAuthClient, error)
+}
+
+// oauthClients implements OAuthClientsInterface interface
+type oauthClients struct {
+	r *Client
+}
+
+// newOAuthClients returns an oauthClients
+func newOAuthClients(c *Client) *oauthClients {
+	return &oauthClients{
+		r: c,
+	}
+}
+
+// Get returns information about a particular OAuth client or an error
+func (c *oauthClients) Get(name string) (result *oauthapi.OAuthClient, err error) {
+	result = &oauthapi.OAuthClient{}
+	err = c.r.Get().Resource("oAuthClients").Name(name).Do().Into(result)
+	return
+}
@@ -67,7 +67,7 @@ func NewCommandCLI(name, fullName string) *cobra.Command {
 	cmds.AddCommand(cmd.NewCmdRollback(name, "rollback", f, out))
 
 	cmds.AddCommand(f.NewCmdGet(out))
-	cmds.AddCommand(f.NewCmdDescribe(out))
+	cmds.AddCommand(describeWithOptions(f.NewCmdDescribe(out)))
 	// Deprecate 'osc apply' with 'osc create' command.
 	cmds.AddCommand(applyToCreate(f.NewCmdCreate(out)))
 	cmds.AddCommand(cmd.NewCmdProcess(f, out))
@@ -106,6 +106,15 @@ func NewCmdKubectl(name string) *cobra.Command {
 	return cmd
 }
 
+// describeWithOptions adds the flags understood by the OpenShift describers to the
+// 'describe' command.
+func describeWithOptions(dst *cobra.Command) *cobra.Command {
+	dst.Flags().Bool("check-conflicts", false, "When describing a route, check other routes in the namespace for host and path conflicts")
+	dst.Flags().Bool("show-server", false, "Show the API server the object was fetched from")
+	dst.Flags().Int("max-rules", 0, "When describing a policy, the maximum number of rules to show for each role. 0 shows all rules.")
+	return dst
+}
+
 // applyToCreate injects the deprecation notice about for 'apply' command into
 // 'create' command.
 // TODO: Remove this once we get rid of 'apply' in all documentation/etc.
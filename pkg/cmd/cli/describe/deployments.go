@@ -3,9 +3,11 @@ package describe
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
@@ -20,17 +22,26 @@ import (
 // DeploymentConfigDescriber generates information about a DeploymentConfig
 type DeploymentConfigDescriber struct {
 	client deploymentDescriberClient
+	// MaxDeployments is the number of past deployments shown for the config.
+	// If zero, defaultMaxDeployments are shown.
+	MaxDeployments int
 }
 
+// defaultMaxDeployments is the number of past deployments shown for a config
+// when MaxDeployments is not set.
+const defaultMaxDeployments = 10
+
 type deploymentDescriberClient interface {
 	getDeploymentConfig(namespace, name string) (*deployapi.DeploymentConfig, error)
 	getDeployment(namespace, name string) (*kapi.ReplicationController, error)
+	listDeployments(namespace string, selector labels.Selector) (*kapi.ReplicationControllerList, error)
 	listPods(namespace string, selector labels.Selector) (*kapi.PodList, error)
 }
 
 type genericDeploymentDescriberClient struct {
 	getDeploymentConfigFunc func(namespace, name string) (*deployapi.DeploymentConfig, error)
 	getDeploymentFunc       func(namespace, name string) (*kapi.ReplicationController, error)
+	listDeploymentsFunc     func(namespace string, selector labels.Selector) (*kapi.ReplicationControllerList, error)
 	listPodsFunc            func(namespace string, selector labels.Selector) (*kapi.PodList, error)
 }
 
@@ -42,6 +53,10 @@ func (c *genericDeploymentDescriberClient) getDeployment(namespace, name string)
 	return c.getDeploymentFunc(namespace, name)
 }
 
+func (c *genericDeploymentDescriberClient) listDeployments(namespace string, selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+	return c.listDeploymentsFunc(namespace, selector)
+}
+
 func (c *genericDeploymentDescriberClient) listPods(namespace string, selector labels.Selector) (*kapi.PodList, error) {
 	return c.listPodsFunc(namespace, selector)
 }
@@ -55,10 +70,14 @@ func NewDeploymentConfigDescriberForConfig(config *deployapi.DeploymentConfig) *
 			getDeploymentFunc: func(namespace, name string) (*kapi.ReplicationController, error) {
 				return nil, kerrors.NewNotFound("ReplicatonController", name)
 			},
+			listDeploymentsFunc: func(namespace string, selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+				return nil, kerrors.NewNotFound("ReplicationControllerList", fmt.Sprintf("%v", selector))
+			},
 			listPodsFunc: func(namespace string, selector labels.Selector) (*kapi.PodList, error) {
 				return nil, kerrors.NewNotFound("PodList", fmt.Sprintf("%v", selector))
 			},
 		},
+		MaxDeployments: defaultMaxDeployments,
 	}
 }
 
@@ -71,10 +90,14 @@ func NewDeploymentConfigDescriber(client client.Interface, kclient kclient.Inter
 			getDeploymentFunc: func(namespace, name string) (*kapi.ReplicationController, error) {
 				return kclient.ReplicationControllers(namespace).Get(name)
 			},
+			listDeploymentsFunc: func(namespace string, selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+				return kclient.ReplicationControllers(namespace).List(selector)
+			},
 			listPodsFunc: func(namespace string, selector labels.Selector) (*kapi.PodList, error) {
 				return kclient.Pods(namespace).List(selector)
 			},
 		},
+		MaxDeployments: defaultMaxDeployments,
 	}
 }
 
@@ -96,6 +119,9 @@ func (d *DeploymentConfigDescriber) Describe(namespace, name string) (string, er
 		printStrategy(deploymentConfig.Template.Strategy, out)
 		printTriggers(deploymentConfig.Triggers, out)
 		printReplicationControllerSpec(deploymentConfig.Template.ControllerTemplate, out)
+		if template := deploymentConfig.Template.ControllerTemplate.Template; template != nil {
+			printSecurityContext(template.Spec, out)
+		}
 
 		deploymentName := deployutil.LatestDeploymentNameForConfig(deploymentConfig)
 		deployment, err := d.client.getDeployment(namespace, deploymentName)
@@ -105,9 +131,19 @@ func (d *DeploymentConfigDescriber) Describe(namespace, name string) (string, er
 			} else {
 				formatString(out, "Latest Deployment", fmt.Sprintf("error: %v", err))
 			}
+			deployment = nil
 		} else {
 			printDeploymentRc(deployment, d.client, out)
 		}
+		printLatestCauses(deploymentConfig.Details, deployment, out)
+
+		if deployments, err := d.client.listDeployments(namespace, labels.Everything()); err == nil {
+			max := d.MaxDeployments
+			if max <= 0 {
+				max = defaultMaxDeployments
+			}
+			printDeploymentHistory(deploymentConfig, deployments.Items, max, out)
+		}
 
 		return nil
 	})
@@ -141,7 +177,7 @@ func printTriggers(triggers []deployapi.DeploymentTriggerPolicy, w io.Writer) {
 		fmt.Fprintf(w, "\t- %s\n", t.Type)
 		switch t.Type {
 		case deployapi.DeploymentTriggerOnConfigChange:
-			fmt.Fprintf(w, "\t\t<no options>\n")
+			fmt.Fprintf(w, "\t\tActive:\tyes, a change to the template starts a deployment\n")
 		case deployapi.DeploymentTriggerOnImageChange:
 			if len(t.ImageChangeParams.RepositoryName) > 0 {
 				fmt.Fprintf(w, "\t\tAutomatic:\t%v\n\t\tRepository:\t%s\n\t\tTag:\t%s\n",
@@ -162,6 +198,28 @@ func printTriggers(triggers []deployapi.DeploymentTriggerPolicy, w io.Writer) {
 	}
 }
 
+// printLatestCauses prints what triggered the latest deployment of a config and,
+// when the deployment exists, when it was created
+func printLatestCauses(details *deployapi.DeploymentDetails, deployment *kapi.ReplicationController, w io.Writer) {
+	if details == nil || (len(details.Causes) == 0 && len(details.Message) == 0) {
+		return
+	}
+	fmt.Fprint(w, "Latest Causes:\n")
+	for _, cause := range details.Causes {
+		if cause.ImageTrigger != nil {
+			fmt.Fprintf(w, "\t- %s\t%s:%s\n", cause.Type, cause.ImageTrigger.RepositoryName, cause.ImageTrigger.Tag)
+			continue
+		}
+		fmt.Fprintf(w, "\t- %s\n", cause.Type)
+	}
+	if len(details.Message) > 0 {
+		fmt.Fprintf(w, "\tMessage:\t%s\n", details.Message)
+	}
+	if deployment != nil && !deployment.CreationTimestamp.IsZero() {
+		fmt.Fprintf(w, "\tDeployed:\t%s\n", deployment.CreationTimestamp)
+	}
+}
+
 func printReplicationControllerSpec(spec kapi.ReplicationControllerSpec, w io.Writer) error {
 	fmt.Fprint(w, "Template:\n")
 
@@ -179,8 +237,42 @@ func printReplicationControllerSpec(spec kapi.ReplicationControllerSpec, w io.Wr
 	return nil
 }
 
+// printSecurityContext prints the privileged mode and capabilities of each container in spec,
+// omitting containers that use the defaults.
+func printSecurityContext(spec kapi.PodSpec, w io.Writer) {
+	printed := false
+	for _, container := range spec.Containers {
+		caps := container.Capabilities
+		if !container.Privileged && len(caps.Add) == 0 && len(caps.Drop) == 0 {
+			continue
+		}
+		if !printed {
+			fmt.Fprint(w, "Security Context:\n")
+			printed = true
+		}
+		fmt.Fprintf(w, "\t%s:\n", container.Name)
+		if container.Privileged {
+			fmt.Fprint(w, "\t\tPrivileged:\ttrue\n")
+		}
+		if len(caps.Add) > 0 {
+			fmt.Fprintf(w, "\t\tAdded Capabilities:\t%s\n", formatCapabilities(caps.Add))
+		}
+		if len(caps.Drop) > 0 {
+			fmt.Fprintf(w, "\t\tDropped Capabilities:\t%s\n", formatCapabilities(caps.Drop))
+		}
+	}
+}
+
+func formatCapabilities(caps []kapi.CapabilityType) string {
+	names := make([]string, len(caps))
+	for i, c := range caps {
+		names[i] = string(c)
+	}
+	return strings.Join(names, ",")
+}
+
 func printDeploymentRc(deployment *kapi.ReplicationController, client deploymentDescriberClient, w io.Writer) error {
-	running, waiting, succeeded, failed, err := getPodStatusForDeployment(deployment, client)
+	running, waiting, succeeded, failed, ready, err := getPodStatusForDeployment(deployment, client)
 	if err != nil {
 		return err
 	}
@@ -191,17 +283,68 @@ func printDeploymentRc(deployment *kapi.ReplicationController, client deployment
 	fmt.Fprintf(w, "\tSelector:\t%s\n", formatLabels(deployment.Spec.Selector))
 	fmt.Fprintf(w, "\tLabels:\t%s\n", formatLabels(deployment.Labels))
 	fmt.Fprintf(w, "\tReplicas:\t%d current / %d desired\n", deployment.Status.Replicas, deployment.Spec.Replicas)
+	fmt.Fprintf(w, "\tPods:\t%d/%d ready\n", ready, deployment.Spec.Replicas)
 	fmt.Fprintf(w, "\tPods Status:\t%d Running / %d Waiting / %d Succeeded / %d Failed\n", running, waiting, succeeded, failed)
 
 	return nil
 }
 
-func getPodStatusForDeployment(deployment *kapi.ReplicationController, client deploymentDescriberClient) (running, waiting, succeeded, failed int, err error) {
+// printDeploymentHistory prints up to max of the most recent deployments created
+// from config, newest first.
+func printDeploymentHistory(config *deployapi.DeploymentConfig, controllers []kapi.ReplicationController, max int, w io.Writer) {
+	deployments := []kapi.ReplicationController{}
+	for _, controller := range controllers {
+		if controller.Annotations[deployapi.DeploymentConfigAnnotation] != config.Name {
+			continue
+		}
+		deployments = append(deployments, controller)
+	}
+	if len(deployments) == 0 {
+		fmt.Fprint(w, "Deployment History:\tNo deployments.\n")
+		return
+	}
+	sort.Sort(sort.Reverse(deploymentsByVersion(deployments)))
+	if len(deployments) > max {
+		deployments = deployments[:max]
+	}
+
+	fmt.Fprint(w, "Deployment History:\n")
+	fmt.Fprint(w, "\tVERSION\tNAME\tSTATUS\tCREATED\n")
+	for _, deployment := range deployments {
+		fmt.Fprintf(w, "\t%s\t%s\t%s\t%s\n",
+			toString(deployment.Annotations[deployapi.DeploymentVersionAnnotation]),
+			deployment.Name,
+			toString(deployment.Annotations[deployapi.DeploymentStatusAnnotation]),
+			deployment.CreationTimestamp.Time.Format(time.RFC1123Z))
+	}
+}
+
+// deploymentsByVersion sorts deployments by the version of the config they were created from
+type deploymentsByVersion []kapi.ReplicationController
+
+func (d deploymentsByVersion) Len() int      { return len(d) }
+func (d deploymentsByVersion) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d deploymentsByVersion) Less(i, j int) bool {
+	return deploymentVersion(d[i]) < deploymentVersion(d[j])
+}
+
+func deploymentVersion(deployment kapi.ReplicationController) int {
+	version, err := strconv.Atoi(deployment.Annotations[deployapi.DeploymentVersionAnnotation])
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+func getPodStatusForDeployment(deployment *kapi.ReplicationController, client deploymentDescriberClient) (running, waiting, succeeded, failed, ready int, err error) {
 	rcPods, err := client.listPods(deployment.Namespace, labels.SelectorFromSet(deployment.Spec.Selector))
 	if err != nil {
 		return
 	}
 	for _, pod := range rcPods.Items {
+		if isPodReady(&pod) {
+			ready++
+		}
 		switch pod.Status.Phase {
 		case kapi.PodRunning:
 			running++
@@ -216,6 +359,16 @@ func getPodStatusForDeployment(deployment *kapi.ReplicationController, client de
 	return
 }
 
+// isPodReady returns true if the pod reports the Ready condition
+func isPodReady(pod *kapi.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Kind == kapi.PodReady {
+			return condition.Status == kapi.ConditionFull
+		}
+	}
+	return false
+}
+
 // DeploymentDescriber generates information about a deployment
 // DEPRECATED.
 type DeploymentDescriber struct {
@@ -231,7 +384,12 @@ func (d *DeploymentDescriber) Describe(namespace, name string) (string, error) {
 
 	return tabbedString(func(out *tabwriter.Writer) error {
 		formatMeta(out, deployment.ObjectMeta)
-		formatString(out, "Status", bold(deployment.Status))
+		printDeploymentConfigRef(deployment.Annotations, out)
+		status := deployment.Status
+		if s := deployment.Annotations[deployapi.DeploymentStatusAnnotation]; len(s) > 0 {
+			status = deployapi.DeploymentStatus(s)
+		}
+		formatString(out, "Status", bold(status))
 		formatString(out, "Strategy", deployment.Strategy.Type)
 		causes := []string{}
 		if deployment.Details != nil {
@@ -240,6 +398,70 @@ func (d *DeploymentDescriber) Describe(namespace, name string) (string, error) {
 			}
 		}
 		formatString(out, "Causes", strings.Join(causes, ","))
+		if template := deployment.ControllerTemplate.Template; template != nil {
+			printContainers(template.Spec.Containers, out)
+		}
 		return nil
 	})
 }
+
+// printDeploymentConfigRef prints the deployment config and version a deployment
+// was created from, as recorded in its annotations.
+func printDeploymentConfigRef(annotations map[string]string, w io.Writer) {
+	config := annotations[deployapi.DeploymentConfigAnnotation]
+	if len(config) == 0 {
+		fmt.Fprint(w, "Deployment Config:\tStandalone deployment (no config)\n")
+		return
+	}
+	fmt.Fprintf(w, "Deployment Config:\t%s\n", config)
+	fmt.Fprintf(w, "Version:\t%s\n", toString(annotations[deployapi.DeploymentVersionAnnotation]))
+}
+
+// printContainers prints the image, ports and environment of each container.
+// The values of environment variables that look like they hold secrets are
+// not shown.
+func printContainers(containers []kapi.Container, w io.Writer) {
+	if len(containers) == 0 {
+		fmt.Fprint(w, "Containers:\t<none>\n")
+		return
+	}
+	fmt.Fprint(w, "Containers:\n\tNAME\tIMAGE\tPORTS\tENV\n")
+	for _, container := range containers {
+		ports := []string{}
+		for _, port := range container.Ports {
+			protocol := port.Protocol
+			if len(protocol) == 0 {
+				protocol = kapi.ProtocolTCP
+			}
+			ports = append(ports, fmt.Sprintf("%d/%s", port.ContainerPort, protocol))
+		}
+		env := []string{}
+		for _, e := range container.Env {
+			value := e.Value
+			if looksSecret(e.Name) {
+				value = "<redacted>"
+			}
+			env = append(env, fmt.Sprintf("%s=%s", e.Name, value))
+		}
+		fmt.Fprintf(w, "\t%s\t%s\t%s\t%s\n",
+			container.Name,
+			container.Image,
+			toString(strings.Join(ports, ", ")),
+			toString(strings.Join(env, ", ")))
+	}
+}
+
+// secretEnvNames are the parts of an environment variable name that suggest
+// its value is a secret
+var secretEnvNames = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "KEY", "CREDENTIAL"}
+
+// looksSecret returns true if the environment variable name suggests its value is a secret
+func looksSecret(name string) bool {
+	name = strings.ToUpper(name)
+	for _, s := range secretEnvNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
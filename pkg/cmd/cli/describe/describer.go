@@ -1,54 +1,91 @@
 package describe
 
 import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	kctl "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
+	"github.com/openshift/origin/pkg/api/latest"
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
 	buildapi "github.com/openshift/origin/pkg/build/api"
 	"github.com/openshift/origin/pkg/client"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+	"github.com/openshift/origin/pkg/template"
 	templateapi "github.com/openshift/origin/pkg/template/api"
 )
 
-func DescriberFor(kind string, c *client.Client, kclient kclient.Interface, host string) (kctl.Describer, bool) {
+// DescriberFor returns a describer for an OpenShift kind. Other kinds are
+// described by the Kubernetes describers when kubeClient is a REST client.
+func DescriberFor(kind string, c *client.Client, kubeClient kclient.Interface, host string) (kctl.Describer, bool) {
 	switch kind {
 	case "Build":
-		return &BuildDescriber{c}, true
+		return &BuildDescriber{c, kubeClient}, true
 	case "BuildConfig":
 		return &BuildConfigDescriber{c, host}, true
 	case "Deployment":
 		return &DeploymentDescriber{c}, true
 	case "DeploymentConfig":
-		return NewDeploymentConfigDescriber(c, kclient), true
+		return NewDeploymentConfigDescriber(c, kubeClient), true
 	case "Image":
 		return &ImageDescriber{c}, true
 	case "ImageRepository":
 		return &ImageRepositoryDescriber{c}, true
 	case "Route":
-		return &RouteDescriber{c}, true
+		return &RouteDescriber{c, false}, true
 	case "Project":
 		return &ProjectDescriber{c}, true
 	case "Template":
-		return &TemplateDescriber{c, meta.NewAccessor(), kapi.Scheme, nil}, true
+		return &TemplateDescriber{c, meta.NewAccessor(), kapi.Scheme, nil, true}, true
 	case "Policy":
-		return &PolicyDescriber{c}, true
+		return &PolicyDescriber{c, 0}, true
 	case "PolicyBinding":
 		return &PolicyBindingDescriber{c}, true
+	case "OAuthClient":
+		return &OAuthClientDescriber{c}, true
+	case "User":
+		return &UserDescriber{c}, true
+	}
+	if kc, ok := kubeClient.(*kclient.Client); ok && kc != nil {
+		return kctl.DescriberFor(kind, kc)
 	}
 	return nil, false
 }
 
+// ServerDescriber prefixes the output of a Describer with the API server the
+// object was fetched from
+type ServerDescriber struct {
+	kctl.Describer
+	Server string
+}
+
+func (d *ServerDescriber) Describe(namespace, name string) (string, error) {
+	out, err := d.Describer.Describe(namespace, name)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Server:\t%s\n%s", toString(d.Server), out), nil
+}
+
 // BuildDescriber generates information about a build
 type BuildDescriber struct {
 	client.Interface
+	KubeClient kclient.Interface
 }
 
 func (d *BuildDescriber) DescribeUser(out *tabwriter.Writer, label string, u buildapi.SourceControlUser) {
@@ -94,6 +131,9 @@ func (d *BuildDescriber) DescribeParameters(p buildapi.BuildParameters, out *tab
 		formatString(out, "URL", p.Source.Git.URI)
 		if len(p.Source.Git.Ref) > 0 {
 			formatString(out, "Ref", p.Source.Git.Ref)
+			if warning := gitRefWarning(p.Source.Git.Ref); len(warning) > 0 {
+				formatString(out, "Ref Warning", warning)
+			}
 		}
 		if len(p.Source.ContextDir) > 0 {
 			formatString(out, "ContextDir", p.Source.ContextDir)
@@ -118,6 +158,20 @@ func (d *BuildDescriber) DescribeParameters(p buildapi.BuildParameters, out *tab
 	}
 }
 
+// gitRefWarning returns a hint when ref does not look like a branch, tag or
+// commit name. It is best effort and does not cover every rule git applies.
+func gitRefWarning(ref string) string {
+	switch {
+	case strings.Contains(ref, "://") || strings.HasPrefix(ref, "git@"):
+		return "the ref looks like a repository URL, not a branch, tag or commit"
+	case strings.ContainsAny(ref, " \t~^:?*[\\") || strings.Contains(ref, ".."):
+		return "the ref contains characters that are not allowed in git branch or tag names"
+	case strings.HasPrefix(ref, "/") || strings.HasSuffix(ref, "/") || strings.HasSuffix(ref, ".lock"):
+		return "the ref is not a valid git branch or tag name"
+	}
+	return ""
+}
+
 func (d *BuildDescriber) Describe(namespace, name string) (string, error) {
 	c := d.Builds(namespace)
 	build, err := c.Get(name)
@@ -128,12 +182,50 @@ func (d *BuildDescriber) Describe(namespace, name string) (string, error) {
 	return tabbedString(func(out *tabwriter.Writer) error {
 		formatMeta(out, build.ObjectMeta)
 		formatString(out, "Status", bold(build.Status))
+		if build.Cancelled {
+			formatString(out, "Cancellation Requested", "yes")
+		}
 		formatString(out, "Build Pod", build.PodName)
 		d.DescribeParameters(build.Parameters, out)
+		if d.KubeClient != nil && len(build.PodName) > 0 {
+			d.DescribeEvents(namespace, build.PodName, time.Now(), out)
+		}
 		return nil
 	})
 }
 
+// maxBuildEvents is the number of build pod events shown for a build
+const maxBuildEvents = 20
+
+// DescribeEvents prints the most recent events of the build pod, newest first
+func (d *BuildDescriber) DescribeEvents(namespace, podName string, now time.Time, out *tabwriter.Writer) {
+	fields := labels.Set{
+		"involvedObject.kind":      "Pod",
+		"involvedObject.namespace": namespace,
+		"involvedObject.name":      podName,
+	}
+	events, err := d.KubeClient.Events(namespace).List(labels.Everything(), fields.AsSelector())
+	if err != nil {
+		formatString(out, "Events", fmt.Sprintf("error: %v", err))
+		return
+	}
+	if len(events.Items) == 0 {
+		formatString(out, "Events", "No events.")
+		return
+	}
+	items := make([]kapi.Event, len(events.Items))
+	copy(items, events.Items)
+	sort.Sort(sort.Reverse(kctl.SortableEvents(items)))
+	if len(items) > maxBuildEvents {
+		items = items[:maxBuildEvents]
+	}
+	fmt.Fprint(out, "Events:\n")
+	fmt.Fprint(out, "\tREASON\tMESSAGE\tAGE\n")
+	for _, e := range items {
+		fmt.Fprintf(out, "\t%s\t%s\t%s\n", toString(e.Reason), toString(e.Message), formatAge(now.Sub(e.LastTimestamp.Time)))
+	}
+}
+
 // BuildConfigDescriber generates information about a buildConfig
 type BuildConfigDescriber struct {
 	client.Interface
@@ -145,9 +237,13 @@ type BuildConfigDescriber struct {
 // DescribeTriggers generates information about the triggers associated with a buildconfig
 func (d *BuildConfigDescriber) DescribeTriggers(bc *buildapi.BuildConfig, host string, out *tabwriter.Writer) {
 	webhooks := webhookURL(bc, host)
-	for whType, whURL := range webhooks {
-		t := strings.Title(whType)
-		formatString(out, "Webhook "+t, whURL)
+	types := []string{}
+	for whType := range webhooks {
+		types = append(types, whType)
+	}
+	sort.Strings(types)
+	for _, whType := range types {
+		formatString(out, webhookLabel(whType), webhooks[whType])
 	}
 	for _, trigger := range bc.Triggers {
 		if trigger.Type != buildapi.ImageChangeBuildTriggerType {
@@ -161,7 +257,45 @@ func (d *BuildConfigDescriber) DescribeTriggers(bc *buildapi.BuildConfig, host s
 		formatString(out, "- Tag", trigger.ImageChange.Tag)
 		formatString(out, "- Image", trigger.ImageChange.Image)
 		formatString(out, "- LastTriggeredImageID", trigger.ImageChange.LastTriggeredImageID)
+		formatString(out, "- Status", d.imageChangeTriggerStatus(bc.Namespace, trigger.ImageChange))
+	}
+}
+
+// webhookLabel returns the label a webhook URL is described with
+func webhookLabel(whType string) string {
+	switch buildapi.BuildTriggerType(whType) {
+	case buildapi.GithubWebHookBuildTriggerType:
+		return "GitHub Webhook"
+	case buildapi.GenericWebHookBuildTriggerType:
+		return "Generic Webhook"
+	}
+	return "Webhook " + strings.Title(whType)
+}
+
+// imageChangeTriggerStatus reports whether the image repository tag referenced
+// by an image change trigger currently resolves to an image
+func (d *BuildConfigDescriber) imageChangeTriggerStatus(namespace string, trigger *buildapi.ImageChangeTrigger) string {
+	if len(trigger.From.Namespace) != 0 {
+		namespace = trigger.From.Namespace
+	}
+	repo, err := d.ImageRepositories(namespace).Get(trigger.From.Name)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "image repository not found"
+		}
+		return fmt.Sprintf("unknown (%v)", err)
+	}
+	if len(repo.Status.DockerImageRepository) == 0 {
+		return "image repository has no registry"
+	}
+	tag := trigger.Tag
+	if len(tag) == 0 {
+		tag = buildapi.DefaultImageTag
+	}
+	if _, ok := repo.Tags[tag]; !ok {
+		return fmt.Sprintf("tag %q not found", tag)
 	}
+	return "resolved"
 }
 
 func (d *BuildConfigDescriber) Describe(namespace, name string) (string, error) {
@@ -196,10 +330,72 @@ func (d *ImageDescriber) Describe(namespace, name string) (string, error) {
 	return tabbedString(func(out *tabwriter.Writer) error {
 		formatMeta(out, image.ObjectMeta)
 		formatString(out, "Docker Image", image.DockerImageReference)
+		if len(image.DockerImageMetadata.Parent) > 0 {
+			formatString(out, "Parent Image", image.DockerImageMetadata.Parent)
+		}
+		describeImageConfig(image.DockerImageMetadata.Config, out)
+		if summary := imageScanSummary(image.Annotations); len(summary) > 0 {
+			formatString(out, "Scan", summary)
+		}
 		return nil
 	})
 }
 
+// describeImageConfig prints the exposed ports, entrypoint, command and
+// environment of a Docker image config. Fields that are not set are omitted,
+// and nothing is printed for images recorded without config metadata.
+func describeImageConfig(config imageapi.DockerConfig, out *tabwriter.Writer) {
+	ports := []string{}
+	for port := range config.ExposedPorts {
+		ports = append(ports, port)
+	}
+	sort.Strings(ports)
+	env := []string{}
+	for _, e := range config.Env {
+		if p := strings.SplitN(e, "=", 2); len(p) == 2 && looksSecret(p[0]) {
+			e = p[0] + "=<redacted>"
+		}
+		env = append(env, e)
+	}
+
+	if len(ports) == 0 && len(config.Entrypoint) == 0 && len(config.Cmd) == 0 && len(env) == 0 {
+		return
+	}
+	fmt.Fprint(out, "Config:\n")
+	if len(ports) > 0 {
+		fmt.Fprintf(out, "\tExposed Ports:\t%s\n", strings.Join(ports, ", "))
+	}
+	if len(config.Entrypoint) > 0 {
+		fmt.Fprintf(out, "\tEntrypoint:\t%s\n", strings.Join(config.Entrypoint, " "))
+	}
+	if len(config.Cmd) > 0 {
+		fmt.Fprintf(out, "\tCommand:\t%s\n", strings.Join(config.Cmd, " "))
+	}
+	if len(env) > 0 {
+		fmt.Fprintf(out, "\tEnvironment:\t%s\n", strings.Join(env, ", "))
+	}
+}
+
+// imageScanAnnotationPrefix prefixes the annotations an image scanner sets on an
+// image to record the number of vulnerabilities found of a severity, for
+// example "scan.openshift.io/critical": "2"
+const imageScanAnnotationPrefix = "scan.openshift.io/"
+
+// imageScanSeverities are the severities reported in a scan summary, in order
+var imageScanSeverities = []string{"critical", "high", "medium", "low"}
+
+// imageScanSummary returns a summary of the scan results recorded in the image
+// annotations, or an empty string if no scan results are present
+func imageScanSummary(annotations map[string]string) string {
+	results := []string{}
+	for _, severity := range imageScanSeverities {
+		if count, ok := annotations[imageScanAnnotationPrefix+severity]; ok {
+			results = append(results, fmt.Sprintf("%s %s", count, severity))
+		}
+	}
+	return strings.Join(results, ", ")
+}
+
 // ImageRepositoryDescriber generates information about a ImageRepository
 type ImageRepositoryDescriber struct {
 	client.Interface
@@ -214,15 +410,58 @@ func (d *ImageRepositoryDescriber) Describe(namespace, name string) (string, err
 
 	return tabbedString(func(out *tabwriter.Writer) error {
 		formatMeta(out, imageRepository.ObjectMeta)
-		formatString(out, "Tags", formatLabels(imageRepository.Tags))
 		formatString(out, "Registry", imageRepository.Status.DockerImageRepository)
+		d.DescribeTags(namespace, imageRepository.Tags, out)
 		return nil
 	})
 }
 
+// DescribeTags prints each tag of an image repository along with the image it
+// references. Tags that reference an image that cannot be retrieved are marked
+// as unresolved.
+func (d *ImageRepositoryDescriber) DescribeTags(namespace string, tags map[string]string, out *tabwriter.Writer) {
+	if len(tags) == 0 {
+		formatString(out, "Tags", "<none>")
+		return
+	}
+	names := []string{}
+	for tag := range tags {
+		names = append(names, tag)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(out, "Tags:\n")
+	fmt.Fprintf(out, "\tTAG\tIMAGE\tREFERENCE\tCREATED\n")
+	for _, tag := range names {
+		image, err := d.Images(namespace).Get(tags[tag])
+		if err != nil {
+			fmt.Fprintf(out, "\t%s\t%s\t<unresolved>\t\n", tag, tags[tag])
+			continue
+		}
+		created := "<unknown>"
+		if !image.DockerImageMetadata.Created.IsZero() {
+			created = image.DockerImageMetadata.Created.Format(time.RFC1123Z)
+		}
+		fmt.Fprintf(out, "\t%s\t%s\t%s\t%s\n", tag, shortImageID(image.Name), toString(image.DockerImageReference), created)
+	}
+}
+
+// shortImageID abbreviates an image id the way the docker client does
+func shortImageID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
 // RouteDescriber generates information about a Route
 type RouteDescriber struct {
 	client.Interface
+
+	// CheckConflicts lists the other routes in the namespace and warns about
+	// any that serve the same host with an overlapping path
+	CheckConflicts bool
 }
 
 func (d *RouteDescriber) Describe(namespace, name string) (string, error) {
@@ -237,10 +476,90 @@ func (d *RouteDescriber) Describe(namespace, name string) (string, error) {
 		formatString(out, "Host", route.Host)
 		formatString(out, "Path", route.Path)
 		formatString(out, "Service", route.ServiceName)
+		if route.TLS != nil {
+			describeRouteTLS(route.TLS, time.Now(), out)
+		}
+		if d.CheckConflicts {
+			routes, err := c.List(labels.Everything(), labels.Everything())
+			if err != nil {
+				formatString(out, "Conflicts", fmt.Sprintf("error: %v", err))
+				return nil
+			}
+			for _, conflict := range conflictingRoutes(route, routes.Items) {
+				formatString(out, "Warning", fmt.Sprintf("route %q also serves %s%s", conflict.Name, conflict.Host, conflict.Path))
+			}
+		}
 		return nil
 	})
 }
 
+// certificateExpiryWarningPeriod is how long before a route certificate expires
+// that the describer starts warning about it
+const certificateExpiryWarningPeriod = 30 * 24 * time.Hour
+
+// describeRouteTLS prints the TLS termination of a route and warns if the route
+// certificate has expired or expires soon
+func describeRouteTLS(tls *routeapi.TLSConfig, now time.Time, out *tabwriter.Writer) {
+	formatString(out, "TLS Termination", tls.Termination)
+	if len(tls.Certificate) == 0 {
+		formatString(out, "Certificate", "<none>")
+		return
+	}
+	formatString(out, "Certificate", "inline")
+	notAfter, err := certificateExpiry(tls.Certificate)
+	if err != nil {
+		formatString(out, "Warning", fmt.Sprintf("unable to read the certificate: %v", err))
+		return
+	}
+	formatString(out, "Certificate Expires", notAfter.Format(time.RFC1123Z))
+	switch {
+	case now.After(notAfter):
+		formatString(out, "Warning", "the certificate has expired")
+	case now.Add(certificateExpiryWarningPeriod).After(notAfter):
+		formatString(out, "Warning", fmt.Sprintf("the certificate expires in %d days", int(notAfter.Sub(now).Hours()/24)))
+	}
+}
+
+// certificateExpiry returns the expiry time of the first certificate in the PEM data
+func certificateExpiry(data string) (time.Time, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return time.Time{}, fmt.Errorf("no PEM data found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// conflictingRoutes returns the routes other than route that serve the same host
+// with the same path, or with a path that is a prefix of the other.
+func conflictingRoutes(route *routeapi.Route, routes []routeapi.Route) []routeapi.Route {
+	conflicts := []routeapi.Route{}
+	if len(route.Host) == 0 {
+		return conflicts
+	}
+	path := routePath(route.Path)
+	for _, other := range routes {
+		if other.Name == route.Name || !strings.EqualFold(other.Host, route.Host) {
+			continue
+		}
+		otherPath := routePath(other.Path)
+		if strings.HasPrefix(path, otherPath) || strings.HasPrefix(otherPath, path) {
+			conflicts = append(conflicts, other)
+		}
+	}
+	return conflicts
+}
+
+func routePath(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
+
 // ProjectDescriber generates information about a Project
 type ProjectDescriber struct {
 	client.Interface
@@ -263,9 +582,11 @@ func (d *ProjectDescriber) Describe(namespace, name string) (string, error) {
 // PolicyDescriber generates information about a Project
 type PolicyDescriber struct {
 	client.Interface
+
+	// MaxRules limits the number of rules shown for each role. Zero shows all rules.
+	MaxRules int
 }
 
-// TODO make something a lot prettier
 func (d *PolicyDescriber) Describe(namespace, name string) (string, error) {
 	c := d.Policies(namespace)
 	policy, err := c.Get(name)
@@ -280,24 +601,82 @@ func (d *PolicyDescriber) Describe(namespace, name string) (string, error) {
 		// using .List() here because I always want the sorted order that it provides
 		for _, key := range util.KeySet(reflect.ValueOf(policy.Roles)).List() {
 			role := policy.Roles[key]
-			fmt.Fprint(out, key+"\tVerbs\tResources\tExtension\n")
-			for _, rule := range role.Rules {
-				extensionString := ""
-				if rule.AttributeRestrictions != (runtime.EmbeddedObject{}) {
-					extensionString = fmt.Sprintf("%v", rule.AttributeRestrictions)
-				}
+			fmt.Fprintf(out, "%s:\n", key)
+			tabbedSection(out, "\t", func(out *tabwriter.Writer) error {
+				fmt.Fprint(out, "Resources\tVerbs\n")
+				d.DescribeRules(role.Rules, out)
+				return nil
+			})
+		}
 
-				fmt.Fprintf(out, "%v\t%v\t%v\t%v\n",
-					"",
-					rule.Verbs.List(),
-					rule.Resources.List(),
-					extensionString)
+		return nil
+	})
+}
 
+// ruleGroup is the set of verbs allowed on a resource by the rules of a role
+type ruleGroup struct {
+	resource      string
+	verbs         util.StringSet
+	resourceNames string
+	restrictions  string
+}
+
+// groupRules groups the verbs of rules by the resource they apply to. Rules
+// are only combined when they have the same resource names and no attribute
+// restrictions.
+func groupRules(rules []authorizationapi.PolicyRule) []*ruleGroup {
+	groups := []*ruleGroup{}
+	byKey := map[string]*ruleGroup{}
+	for i, rule := range rules {
+		restrictions := ""
+		if rule.AttributeRestrictions != (runtime.EmbeddedObject{}) {
+			restrictions = fmt.Sprintf("%v", rule.AttributeRestrictions)
+		}
+		resourceNames := strings.Join(rule.ResourceNames.List(), ", ")
+		resources := rule.Resources.List()
+		for _, url := range rule.NonResourceURLs.List() {
+			resources = append(resources, "url:"+url)
+		}
+		for _, resource := range resources {
+			key := resource + "\x00" + resourceNames
+			if len(restrictions) > 0 {
+				key = fmt.Sprintf("%s\x00%d", key, i)
+			}
+			group, ok := byKey[key]
+			if !ok {
+				group = &ruleGroup{resource: resource, verbs: util.NewStringSet(), resourceNames: resourceNames, restrictions: restrictions}
+				byKey[key] = group
+				groups = append(groups, group)
 			}
+			group.verbs.Insert(rule.Verbs.List()...)
 		}
+	}
+	sort.Stable(ruleGroupsByResource(groups))
+	return groups
+}
 
-		return nil
-	})
+type ruleGroupsByResource []*ruleGroup
+
+func (g ruleGroupsByResource) Len() int           { return len(g) }
+func (g ruleGroupsByResource) Swap(i, j int)      { g[i], g[j] = g[j], g[i] }
+func (g ruleGroupsByResource) Less(i, j int) bool { return g[i].resource < g[j].resource }
+
+// DescribeRules prints the verbs allowed on each resource by the first MaxRules
+// rules of a role
+func (d *PolicyDescriber) DescribeRules(rules []authorizationapi.PolicyRule, out *tabwriter.Writer) {
+	if d.MaxRules > 0 && len(rules) > d.MaxRules {
+		defer fmt.Fprintf(out, "... and %d more rules\n", len(rules)-d.MaxRules)
+		rules = rules[:d.MaxRules]
+	}
+	for _, group := range groupRules(rules) {
+		fmt.Fprintf(out, "%s\t%s\n", toString(group.resource), strings.Join(group.verbs.List(), ", "))
+		if len(group.resourceNames) > 0 {
+			fmt.Fprintf(out, "\tResource Names: %s\n", group.resourceNames)
+		}
+		if len(group.restrictions) > 0 {
+			fmt.Fprintf(out, "\tRestrictions: %s\n", group.restrictions)
+		}
+	}
 }
 
 // PolicyBindingDescriber generates information about a Project
@@ -317,6 +696,8 @@ func (d *PolicyBindingDescriber) Describe(namespace, name string) (string, error
 		formatMeta(out, policyBinding.ObjectMeta)
 		formatString(out, "Last Modified", policyBinding.LastModified)
 		formatString(out, "Policy", policyBinding.PolicyRef.Namespace)
+		bindings, users, groups := bindingSummary(policyBinding.RoleBindings)
+		formatString(out, "Summary", fmt.Sprintf("%d role bindings, %d distinct users, %d distinct groups", bindings, users, groups))
 
 		// using .List() here because I always want the sorted order that it provides
 		for _, key := range util.KeySet(reflect.ValueOf(policyBinding.RoleBindings)).List() {
@@ -331,17 +712,96 @@ func (d *PolicyBindingDescriber) Describe(namespace, name string) (string, error
 	})
 }
 
+// bindingSummary counts the role bindings and the distinct users and groups
+// they bind
+func bindingSummary(roleBindings map[string]authorizationapi.RoleBinding) (bindings, users, groups int) {
+	allUsers := util.NewStringSet()
+	allGroups := util.NewStringSet()
+	for _, roleBinding := range roleBindings {
+		allUsers.Insert(roleBinding.Users.List()...)
+		allGroups.Insert(roleBinding.Groups.List()...)
+	}
+	return len(roleBindings), allUsers.Len(), allGroups.Len()
+}
+
+// OAuthClientDescriber generates information about an OAuthClient
+type OAuthClientDescriber struct {
+	client.Interface
+}
+
+func (d *OAuthClientDescriber) Describe(namespace, name string) (string, error) {
+	c := d.OAuthClients()
+	oauthClient, err := c.Get(name)
+	if err != nil {
+		return "", err
+	}
+
+	return tabbedString(func(out *tabwriter.Writer) error {
+		formatMeta(out, oauthClient.ObjectMeta)
+		// the secret itself is never shown
+		secret := ""
+		if len(oauthClient.Secret) > 0 {
+			secret = "<set>"
+		}
+		formatString(out, "Secret", secret)
+		formatString(out, "Respond With Challenges", fmt.Sprintf("%t", oauthClient.RespondWithChallenges))
+		formatString(out, "Redirect URIs", strings.Join(oauthClient.RedirectURIs, ", "))
+		return nil
+	})
+}
+
+// UserDescriber generates information about a User
+type UserDescriber struct {
+	client.Interface
+}
+
+func (d *UserDescriber) Describe(namespace, name string) (string, error) {
+	c := d.Users()
+	user, err := c.Get(name)
+	if err != nil {
+		return "", err
+	}
+
+	return tabbedString(func(out *tabwriter.Writer) error {
+		formatMeta(out, user.ObjectMeta)
+		formatString(out, "Full Name", user.FullName)
+		return nil
+	})
+}
+
 // TemplateDescriber generates information about a template
 type TemplateDescriber struct {
 	client.Interface
 	meta.MetadataAccessor
 	runtime.ObjectTyper
 	DescribeObject func(obj runtime.Object, out *tabwriter.Writer) (bool, error)
+	// ValidateParameters warns about parameters that the objects reference but
+	// that are not declared, and declared parameters that are never referenced
+	ValidateParameters bool
+}
+
+// ParameterSummary counts the template parameters that must be provided (no value
+// and no generate expression), that have a default value, and that are generated.
+func ParameterSummary(params []templateapi.Parameter) (required, defaulted, generated int) {
+	for _, p := range params {
+		switch {
+		case len(p.Value) > 0:
+			defaulted++
+		case len(p.Generate) > 0:
+			generated++
+		default:
+			required++
+		}
+	}
+	return
 }
 
 func (d *TemplateDescriber) DescribeParameters(params []templateapi.Parameter, out *tabwriter.Writer) {
 	formatString(out, "Parameters", " ")
 	indent := "    "
+	required, defaulted, generated := ParameterSummary(params)
+	formatString(out, indent+"Summary", fmt.Sprintf("%d required, %d with defaults, %d generated", required, defaulted, generated))
+	out.Write([]byte("\n"))
 	for _, p := range params {
 		formatString(out, indent+"Name", p.Name)
 		formatString(out, indent+"Description", p.Description)
@@ -365,28 +825,112 @@ func (d *TemplateDescriber) DescribeObjects(objects []runtime.Object, labels map
 	formatString(out, "Objects", " ")
 
 	indent := "    "
-	for _, obj := range objects {
-		if d.DescribeObject != nil {
-			if ok, _ := d.DescribeObject(obj, out); ok {
-				out.Write([]byte("\n"))
-				continue
+	tabbedSection(out, indent, func(out *tabwriter.Writer) error {
+		for _, obj := range objects {
+			if d.DescribeObject != nil {
+				if ok, _ := d.DescribeObject(obj, out); ok {
+					out.Write([]byte("\n"))
+					continue
+				}
 			}
+
+			_, kind, _ := d.ObjectTyper.ObjectVersionAndKind(obj)
+			meta := kapi.ObjectMeta{}
+			meta.Name, _ = d.MetadataAccessor.Name(obj)
+			meta.Annotations, _ = d.MetadataAccessor.Annotations(obj)
+			meta.Labels, _ = d.MetadataAccessor.Labels(obj)
+			fmt.Fprintf(out, "%s\t%s\n", kind, meta.Name)
+			if len(meta.Labels) > 0 {
+				formatString(out, "Labels", formatLabels(meta.Labels))
+			}
+			formatAnnotations(out, meta, "")
+		}
+		if len(labels) > 0 {
+			out.Write([]byte("\n"))
+			formatString(out, "Applied to all objects", formatLabels(labels))
 		}
+		return nil
+	})
+}
 
-		_, kind, _ := d.ObjectTyper.ObjectVersionAndKind(obj)
-		meta := kapi.ObjectMeta{}
-		meta.Name, _ = d.MetadataAccessor.Name(obj)
-		meta.Annotations, _ = d.MetadataAccessor.Annotations(obj)
-		meta.Labels, _ = d.MetadataAccessor.Labels(obj)
-		fmt.Fprintf(out, fmt.Sprintf("%s%s\t%s\n", indent, kind, meta.Name))
-		if len(meta.Labels) > 0 {
-			formatString(out, indent+"Labels", formatLabels(meta.Labels))
+// objectType is an API version and kind used by template objects
+type objectType struct {
+	apiVersion string
+	kind       string
+}
+
+// declaredObjectTypes returns the API version and kind each object of a template
+// was declared with, from the raw template returned by the server. Decoding a
+// template converts its objects to the internal version, so the declared
+// version is only available before decoding. It returns nil if the raw template
+// cannot be read.
+func (d *TemplateDescriber) declaredObjectTypes(namespace, name string) []objectType {
+	c, ok := d.Interface.(*client.Client)
+	if !ok || c == nil {
+		return nil
+	}
+	data, err := c.Get().Namespace(namespace).Resource("templates").Name(name).Do().Raw()
+	if err != nil {
+		return nil
+	}
+	return decodeObjectTypes(data)
+}
+
+// decodeObjectTypes reads the API version and kind of each item of a template
+// encoded as JSON
+func decodeObjectTypes(data []byte) []objectType {
+	raw := struct {
+		Items []struct {
+			Kind       string `json:"kind"`
+			APIVersion string `json:"apiVersion"`
+		} `json:"items"`
+	}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	types := []objectType{}
+	for _, item := range raw.Items {
+		types = append(types, objectType{apiVersion: item.APIVersion, kind: item.Kind})
+	}
+	return types
+}
+
+// DescribeObjectTypes summarizes the API versions and kinds of the template
+// objects. declared holds the API version each object was declared with, in
+// the same order as objects. Objects without a declared version are shown with
+// the version the client encodes objects with.
+func (d *TemplateDescriber) DescribeObjectTypes(objects []runtime.Object, declared []objectType, out *tabwriter.Writer) {
+	counts := map[objectType]int{}
+	types := []objectType{}
+	for i, obj := range objects {
+		t := objectType{}
+		if unknown, ok := obj.(*runtime.Unknown); ok {
+			typeMeta := unknown.TypeMeta
+			if len(typeMeta.Kind) == 0 {
+				json.Unmarshal(unknown.RawJSON, &typeMeta)
+			}
+			t.apiVersion, t.kind = typeMeta.APIVersion, typeMeta.Kind+" (unrecognized)"
+		} else {
+			_, t.kind, _ = d.ObjectTyper.ObjectVersionAndKind(obj)
+			t.apiVersion = latest.Version
+			if i < len(declared) && declared[i].kind == t.kind && len(declared[i].apiVersion) > 0 {
+				t.apiVersion = declared[i].apiVersion
+			}
 		}
-		formatAnnotations(out, meta, indent)
+		if _, exists := counts[t]; !exists {
+			types = append(types, t)
+		}
+		counts[t]++
 	}
-	if len(labels) > 0 {
-		out.Write([]byte("\n"))
-		formatString(out, indent+"Common Labels", formatLabels(labels))
+	if len(types) == 0 {
+		return
+	}
+
+	formatString(out, "Object Types", " ")
+	indent := "    "
+	fmt.Fprintf(out, "%sAPI VERSION\tKIND\tCOUNT\n", indent)
+	for _, t := range types {
+		fmt.Fprintf(out, "%s%s\t%s\t%d\n", indent, t.apiVersion, t.kind, counts[t])
 	}
 }
 
@@ -403,7 +947,50 @@ func (d *TemplateDescriber) Describe(namespace, name string) (string, error) {
 		out.Flush()
 		d.DescribeParameters(template.Parameters, out)
 		out.Write([]byte("\n"))
+		d.DescribeObjectTypes(template.Objects, d.declaredObjectTypes(namespace, name), out)
+		out.Write([]byte("\n"))
 		d.DescribeObjects(template.Objects, template.ObjectLabels, out)
+		if d.ValidateParameters {
+			d.DescribeParameterWarnings(template.Parameters, template.Objects, out)
+		}
 		return nil
 	})
 }
+
+// DescribeParameterWarnings prints a warning for each parameter that is
+// referenced by the objects but not declared, and for each declared parameter
+// that no object references.
+func (d *TemplateDescriber) DescribeParameterWarnings(params []templateapi.Parameter, objects []runtime.Object, out *tabwriter.Writer) {
+	referenced := util.NewStringSet()
+	for _, obj := range objects {
+		names, err := template.ParameterReferences(obj)
+		if err != nil {
+			continue
+		}
+		referenced.Insert(names.List()...)
+	}
+	declared := util.NewStringSet()
+	for _, param := range params {
+		declared.Insert(param.Name)
+	}
+
+	warnings := []string{}
+	for _, name := range referenced.List() {
+		if !declared.Has(name) {
+			warnings = append(warnings, fmt.Sprintf("Parameter %q is referenced but not declared", name))
+		}
+	}
+	for _, name := range declared.List() {
+		if !referenced.Has(name) {
+			warnings = append(warnings, fmt.Sprintf("Parameter %q is declared but never referenced", name))
+		}
+	}
+	if len(warnings) == 0 {
+		return
+	}
+	out.Write([]byte("\n"))
+	fmt.Fprint(out, "Warnings:\n")
+	for _, warning := range warnings {
+		fmt.Fprintf(out, "    %s\n", warning)
+	}
+}
@@ -1,18 +1,39 @@
 package describe
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"text/tabwriter"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/openshift/origin/pkg/api/latest"
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
 	"github.com/openshift/origin/pkg/client"
 
+	buildapi "github.com/openshift/origin/pkg/build/api"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	deployapitest "github.com/openshift/origin/pkg/deploy/api/test"
 	deployutil "github.com/openshift/origin/pkg/deploy/util"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+	oauthapi "github.com/openshift/origin/pkg/oauth/api"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+	templateapi "github.com/openshift/origin/pkg/template/api"
 )
 
 type describeClient struct {
@@ -26,7 +47,7 @@ func TestDescribeFor(t *testing.T) {
 	c := &client.Client{}
 	testTypesList := []string{
 		"Build", "BuildConfig", "Deployment", "DeploymentConfig",
-		"Image", "ImageRepository", "Route", "Project",
+		"Image", "ImageRepository", "Route", "Project", "OAuthClient", "User",
 	}
 	for _, o := range testTypesList {
 		_, ok := DescriberFor(o, c, &kclient.Fake{}, "")
@@ -36,21 +57,39 @@ func TestDescribeFor(t *testing.T) {
 	}
 }
 
+func TestDescribeForKubernetesKinds(t *testing.T) {
+	c := &client.Client{}
+	for _, kind := range []string{"Pod", "Service", "ReplicationController"} {
+		if _, ok := DescriberFor(kind, c, &kclient.Client{}, ""); !ok {
+			t.Errorf("Unable to obtain describer for %s", kind)
+		}
+		if _, ok := DescriberFor(kind, c, &kclient.Fake{}, ""); ok {
+			t.Errorf("Unexpected describer for %s without a Kubernetes client", kind)
+		}
+	}
+	if _, ok := DescriberFor("Unknown", c, &kclient.Client{}, ""); ok {
+		t.Errorf("Unexpected describer for an unknown kind")
+	}
+}
+
 func TestDescribers(t *testing.T) {
 	fake := &client.Fake{}
 	c := &describeClient{T: t, Namespace: "foo", Fake: fake}
 
 	testDescriberList := []kubectl.Describer{
-		&BuildDescriber{c},
+		&BuildDescriber{c, &kclient.Fake{}},
 		&BuildConfigDescriber{c, ""},
 		&DeploymentDescriber{c},
 		&ImageDescriber{c},
 		&ImageRepositoryDescriber{c},
-		&RouteDescriber{c},
+		&RouteDescriber{c, false},
+		&RouteDescriber{c, true},
 		&ProjectDescriber{c},
-		&PolicyDescriber{c},
+		&PolicyDescriber{c, 0},
 		&PolicyBindingDescriber{c},
-		&TemplateDescriber{c, nil, nil, nil},
+		&OAuthClientDescriber{c},
+		&UserDescriber{c},
+		&TemplateDescriber{c, nil, nil, nil, false},
 	}
 
 	for _, d := range testDescriberList {
@@ -64,6 +103,525 @@ func TestDescribers(t *testing.T) {
 	}
 }
 
+func TestDescribeBuildEvents(t *testing.T) {
+	now := time.Now()
+	fake := &kclient.Fake{}
+	for i := 0; i < 25; i++ {
+		fake.EventsList.Items = append(fake.EventsList.Items, kapi.Event{
+			Reason:        fmt.Sprintf("reason%d", i),
+			Message:       "message",
+			LastTimestamp: util.NewTime(now.Add(time.Duration(i-25) * time.Minute)),
+		})
+	}
+	d := &BuildDescriber{KubeClient: fake}
+	describe := func() string {
+		out, _ := tabbedString(func(out *tabwriter.Writer) error {
+			d.DescribeEvents("test", "build-pod", now, out)
+			return nil
+		})
+		return out
+	}
+
+	out := describe()
+	if strings.Count(out, "message") != maxBuildEvents {
+		t.Errorf("expected %d events in output: %s", maxBuildEvents, out)
+	}
+	if !strings.Contains(out, "reason24") || strings.Contains(out, "reason4\t") {
+		t.Errorf("expected only the most recent events in output: %s", out)
+	}
+	if strings.Index(out, "reason24") > strings.Index(out, "reason23") {
+		t.Errorf("expected events newest first: %s", out)
+	}
+
+	fake.EventsList.Items = nil
+	if out := describe(); !strings.Contains(out, "No events.") {
+		t.Errorf("expected no events in output: %s", out)
+	}
+}
+
+type imageDescribeClient struct {
+	*client.Fake
+	images map[string]*imageapi.Image
+}
+
+func (c *imageDescribeClient) Images(namespace string) client.ImageInterface {
+	return &imageDescribeImages{client.FakeImages{Fake: c.Fake, Namespace: namespace}, c.images}
+}
+
+type imageDescribeImages struct {
+	client.FakeImages
+	images map[string]*imageapi.Image
+}
+
+func (c *imageDescribeImages) Get(name string) (*imageapi.Image, error) {
+	if image, ok := c.images[name]; ok {
+		return image, nil
+	}
+	return nil, fmt.Errorf("image %q not found", name)
+}
+
+func TestDescribeImageRepositoryTags(t *testing.T) {
+	c := &imageDescribeClient{
+		Fake: &client.Fake{},
+		images: map[string]*imageapi.Image{
+			"abcdef0123456789": {
+				ObjectMeta:           kapi.ObjectMeta{Name: "abcdef0123456789"},
+				DockerImageReference: "registry/ns/app@abcdef0123456789",
+				DockerImageMetadata:  imageapi.DockerImage{Created: util.Date(2015, 2, 1, 10, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+	d := &ImageRepositoryDescriber{c}
+	out, _ := tabbedString(func(out *tabwriter.Writer) error {
+		d.DescribeTags("test", map[string]string{"latest": "abcdef0123456789", "old": "missing"}, out)
+		return nil
+	})
+	for _, expected := range []string{"\tabcdef012345\t", "registry/ns/app@abcdef0123456789", "Sun, 01 Feb 2015 10:00:00 +0000", "<unresolved>"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output: %s", expected, out)
+		}
+	}
+	if strings.Index(out, "latest") > strings.Index(out, "old") {
+		t.Errorf("expected tags to be sorted: %s", out)
+	}
+}
+
+func TestDescribeBuildConfigWebhooks(t *testing.T) {
+	bc := &buildapi.BuildConfig{
+		ObjectMeta: kapi.ObjectMeta{Name: "app"},
+		Triggers: []buildapi.BuildTriggerPolicy{
+			{Type: buildapi.GithubWebHookBuildTriggerType, GithubWebHook: &buildapi.WebHookTrigger{Secret: "secret1"}},
+			{Type: buildapi.GenericWebHookBuildTriggerType, GenericWebHook: &buildapi.WebHookTrigger{Secret: "secret2"}},
+		},
+	}
+	d := &BuildConfigDescriber{}
+	out, _ := tabbedString(func(out *tabwriter.Writer) error {
+		d.DescribeTriggers(bc, "https://master", out)
+		return nil
+	})
+	generic, github := strings.Index(out, "Generic Webhook:"), strings.Index(out, "GitHub Webhook:")
+	if generic == -1 || github == -1 || generic > github {
+		t.Errorf("expected sorted Generic and GitHub webhooks: %s", out)
+	}
+	if !strings.Contains(out, "/app/secret1/github") || !strings.Contains(out, "/app/secret2/generic") {
+		t.Errorf("expected webhook URLs with their secrets: %s", out)
+	}
+}
+
+func TestWebhookURL(t *testing.T) {
+	bc := &buildapi.BuildConfig{
+		ObjectMeta: kapi.ObjectMeta{Name: "app"},
+		Triggers: []buildapi.BuildTriggerPolicy{
+			{Type: buildapi.GithubWebHookBuildTriggerType, GithubWebHook: &buildapi.WebHookTrigger{Secret: "secret1"}},
+		},
+	}
+	urls := webhookURL(bc, "https://master:8443")
+	if expected := "https://master:8443/osapi/" + latest.Version + "/buildConfigHooks/app/secret1/github"; urls["github"] != expected {
+		t.Errorf("expected %s, got %s", expected, urls["github"])
+	}
+	urls = webhookURL(bc, "")
+	if urls["github"] != "<webhook URL unavailable: master host unknown>" {
+		t.Errorf("expected a placeholder without a host, got %s", urls["github"])
+	}
+}
+
+func TestDescribePolicyRules(t *testing.T) {
+	rules := []authorizationapi.PolicyRule{
+		{Verbs: util.NewStringSet("get", "list"), Resources: util.NewStringSet("pods", "builds")},
+		{Verbs: util.NewStringSet("create"), Resources: util.NewStringSet("builds")},
+		{Verbs: util.NewStringSet("delete"), Resources: util.NewStringSet("builds"), ResourceNames: util.NewStringSet("frontend")},
+	}
+	d := &PolicyDescriber{}
+	out, _ := tabbedString(func(out *tabwriter.Writer) error {
+		d.DescribeRules(rules, out)
+		return nil
+	})
+	for _, expected := range []string{"builds\tcreate, get, list\n", "pods\tget, list\n", "builds\tdelete\n", "Resource Names: frontend"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output: %s", expected, out)
+		}
+	}
+	if strings.Contains(out, "Restrictions") {
+		t.Errorf("unexpected restrictions in output: %s", out)
+	}
+	if strings.Index(out, "builds") > strings.Index(out, "pods") {
+		t.Errorf("expected rules sorted by resource: %s", out)
+	}
+
+	d.MaxRules = 1
+	out, _ = tabbedString(func(out *tabwriter.Writer) error {
+		d.DescribeRules(rules, out)
+		return nil
+	})
+	// the first rule applies to two resources, which are both shown
+	if !strings.Contains(out, "builds\tget, list\n") || !strings.Contains(out, "pods\tget, list\n") {
+		t.Errorf("expected every resource of the first rule: %s", out)
+	}
+	if strings.Contains(out, "create") || strings.Contains(out, "delete") || !strings.Contains(out, "... and 2 more rules") {
+		t.Errorf("expected the rules to be truncated: %s", out)
+	}
+}
+
+type oauthDescribeClient struct {
+	*client.Fake
+	oauthClient *oauthapi.OAuthClient
+}
+
+func (c *oauthDescribeClient) OAuthClients() client.OAuthClientInterface {
+	return &oauthDescribeClients{client.FakeOAuthClients{Fake: c.Fake}, c.oauthClient}
+}
+
+type oauthDescribeClients struct {
+	client.FakeOAuthClients
+	oauthClient *oauthapi.OAuthClient
+}
+
+func (c *oauthDescribeClients) Get(name string) (*oauthapi.OAuthClient, error) {
+	return c.oauthClient, nil
+}
+
+func TestDescribeOAuthClient(t *testing.T) {
+	c := &oauthDescribeClient{
+		Fake: &client.Fake{},
+		oauthClient: &oauthapi.OAuthClient{
+			ObjectMeta:            kapi.ObjectMeta{Name: "openshift-web-console"},
+			Secret:                "s3cr3t",
+			RespondWithChallenges: true,
+			RedirectURIs:          []string{"https://console.example.com", "https://localhost:8443"},
+		},
+	}
+	out, err := (&OAuthClientDescriber{c}).Describe("", "openshift-web-console")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{"openshift-web-console", "<set>", "true", "https://console.example.com, https://localhost:8443"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output: %s", expected, out)
+		}
+	}
+	if strings.Contains(out, "s3cr3t") {
+		t.Errorf("unexpected secret in output: %s", out)
+	}
+}
+
+func TestFormatStringSanitizesValues(t *testing.T) {
+	meta := kapi.ObjectMeta{
+		Name:   "app",
+		Labels: map[string]string{"tier": "web"},
+		Annotations: map[string]string{
+			"description": "first line\nsecond\tline\r\n",
+			"notes":       "a\tb",
+		},
+	}
+	out, _ := tabbedString(func(out *tabwriter.Writer) error {
+		formatMeta(out, meta)
+		return nil
+	})
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected one line per field, got: %q", out)
+	}
+	column := -1
+	for _, line := range lines {
+		i := strings.Index(line, "\t")
+		if i == -1 {
+			t.Fatalf("expected a label and a value: %q", line)
+		}
+		value := strings.TrimLeft(line[i:], "\t")
+		if strings.Contains(value, "\t") {
+			t.Errorf("unexpected tab in value: %q", line)
+		}
+		// expand the tabs before the value to find the column it starts in
+		width := 0
+		for _, c := range line[:len(line)-len(value)] {
+			if c == '\t' {
+				width = (width/8 + 1) * 8
+			} else {
+				width++
+			}
+		}
+		if column == -1 {
+			column = width
+		} else if width != column {
+			t.Errorf("expected the values to be aligned: %q", out)
+		}
+	}
+	if !strings.Contains(out, `first line\nsecond line\n`) {
+		t.Errorf("expected the line breaks to be escaped: %q", out)
+	}
+}
+
+func TestTabbedSection(t *testing.T) {
+	out, err := tabbedString(func(out *tabwriter.Writer) error {
+		formatString(out, "A Very Long Label Name", "value")
+		fmt.Fprint(out, "Rules:\n")
+		return tabbedSection(out, "\t", func(out *tabwriter.Writer) error {
+			fmt.Fprint(out, "a\tb\n")
+			fmt.Fprint(out, "c\td\n")
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Rules:\n\ta\tb\n\tc\td\n") {
+		t.Errorf("expected the section to be aligned independently: %q", out)
+	}
+	if strings.Contains(out, "\x00") || strings.Contains(out, string([]byte{tabwriter.Escape})) {
+		t.Errorf("unexpected control characters in output: %q", out)
+	}
+}
+
+func TestBindingSummary(t *testing.T) {
+	roleBindings := map[string]authorizationapi.RoleBinding{
+		"admins":  {Users: util.NewStringSet("alice", "bob"), Groups: util.NewStringSet("ops")},
+		"viewers": {Users: util.NewStringSet("bob", "carol"), Groups: util.NewStringSet("ops", "dev")},
+		"empty":   {},
+	}
+	bindings, users, groups := bindingSummary(roleBindings)
+	if bindings != 3 || users != 3 || groups != 2 {
+		t.Errorf("unexpected summary: %d bindings, %d users, %d groups", bindings, users, groups)
+	}
+}
+
+func TestPrintLatestCauses(t *testing.T) {
+	details := &deployapi.DeploymentDetails{
+		Message: "new image",
+		Causes: []*deployapi.DeploymentCause{
+			{Type: deployapi.DeploymentTriggerOnImageChange, ImageTrigger: &deployapi.DeploymentCauseImageTrigger{RepositoryName: "registry/app", Tag: "v2"}},
+			{Type: deployapi.DeploymentTriggerOnConfigChange},
+		},
+	}
+	deployment := &kapi.ReplicationController{ObjectMeta: kapi.ObjectMeta{CreationTimestamp: util.Date(2015, 3, 1, 12, 0, 0, 0, time.UTC)}}
+	out, _ := tabbedString(func(out *tabwriter.Writer) error {
+		printLatestCauses(details, deployment, out)
+		return nil
+	})
+	for _, expected := range []string{"Latest Causes:", "ImageChange", "registry/app:v2", "ConfigChange", "new image", "2015"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output: %s", expected, out)
+		}
+	}
+
+	out, _ = tabbedString(func(out *tabwriter.Writer) error {
+		printLatestCauses(nil, nil, out)
+		return nil
+	})
+	if len(out) > 0 {
+		t.Errorf("unexpected output without details: %s", out)
+	}
+}
+
+func TestPrintContainers(t *testing.T) {
+	containers := []kapi.Container{
+		{
+			Name:  "app",
+			Image: "registry/app:v1",
+			Ports: []kapi.Port{{ContainerPort: 8080}, {ContainerPort: 53, Protocol: kapi.ProtocolUDP}},
+			Env:   []kapi.EnvVar{{Name: "MODE", Value: "production"}, {Name: "DB_PASSWORD", Value: "hunter2"}},
+		},
+	}
+	out, _ := tabbedString(func(out *tabwriter.Writer) error {
+		printContainers(containers, out)
+		return nil
+	})
+	for _, expected := range []string{"registry/app:v1", "8080/TCP, 53/UDP", "MODE=production", "DB_PASSWORD=<redacted>"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output: %s", expected, out)
+		}
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("unexpected secret value in output: %s", out)
+	}
+}
+
+func TestPrintDeploymentConfigRef(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		expected    []string
+	}{
+		"from config": {
+			annotations: map[string]string{
+				deployapi.DeploymentConfigAnnotation:  "frontend",
+				deployapi.DeploymentVersionAnnotation: "3",
+			},
+			expected: []string{"Deployment Config:\tfrontend", "Version:", "\t3\n"},
+		},
+		"standalone": {
+			annotations: map[string]string{},
+			expected:    []string{"Standalone deployment (no config)"},
+		},
+	}
+	for name, test := range tests {
+		out, _ := tabbedString(func(out *tabwriter.Writer) error {
+			printDeploymentConfigRef(test.annotations, out)
+			return nil
+		})
+		for _, s := range test.expected {
+			if !strings.Contains(out, s) {
+				t.Errorf("%s: expected %q in output: %s", name, s, out)
+			}
+		}
+	}
+}
+
+func TestGitRefWarning(t *testing.T) {
+	tests := map[string]bool{
+		"master":                               false,
+		"v1.0.2":                               false,
+		"feature/login":                        false,
+		"a3c1f5e":                              false,
+		"https://github.com/openshift/app.git": true,
+		"git@github.com:openshift/app.git":     true,
+		"my branch":                            true,
+		"master..dev":                          true,
+		"refs/heads/":                          true,
+	}
+	for ref, expectWarning := range tests {
+		if warning := gitRefWarning(ref); (len(warning) > 0) != expectWarning {
+			t.Errorf("%s: unexpected warning %q", ref, warning)
+		}
+	}
+}
+
+func TestImageScanSummary(t *testing.T) {
+	tests := []struct {
+		annotations map[string]string
+		summary     string
+	}{
+		{
+			annotations: map[string]string{},
+		},
+		{
+			annotations: map[string]string{"description": "base image"},
+		},
+		{
+			annotations: map[string]string{
+				"scan.openshift.io/high":     "5",
+				"scan.openshift.io/critical": "2",
+			},
+			summary: "2 critical, 5 high",
+		},
+	}
+	for i, test := range tests {
+		if summary := imageScanSummary(test.annotations); summary != test.summary {
+			t.Errorf("%d: expected %q, got %q", i, test.summary, summary)
+		}
+	}
+}
+
+func TestDescribeImageConfig(t *testing.T) {
+	tests := map[string]struct {
+		config   imageapi.DockerConfig
+		expected []string
+		absent   []string
+	}{
+		"no metadata": {
+			absent: []string{"Config:"},
+		},
+		"full config": {
+			config: imageapi.DockerConfig{
+				ExposedPorts: map[string]struct{}{"8080/tcp": {}, "443/tcp": {}},
+				Entrypoint:   []string{"/bin/sh", "-c"},
+				Cmd:          []string{"run"},
+				Env:          []string{"HOME=/opt", "DB_PASSWORD=foo"},
+			},
+			expected: []string{"Config:", "Exposed Ports:\t443/tcp, 8080/tcp", "Entrypoint:\t/bin/sh -c", "Command:\trun", "Environment:\tHOME=/opt, DB_PASSWORD=<redacted>"},
+		},
+		"command only": {
+			config:   imageapi.DockerConfig{Cmd: []string{"run"}},
+			expected: []string{"Config:", "Command:\trun"},
+			absent:   []string{"Exposed Ports:", "Entrypoint:", "Environment:"},
+		},
+	}
+	for name, test := range tests {
+		out, err := tabbedString(func(out *tabwriter.Writer) error {
+			describeImageConfig(test.config, out)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		for _, s := range test.expected {
+			if !strings.Contains(out, s) {
+				t.Errorf("%s: expected %q in output:\n%s", name, s, out)
+			}
+		}
+		for _, s := range test.absent {
+			if strings.Contains(out, s) {
+				t.Errorf("%s: unexpected %q in output:\n%s", name, s, out)
+			}
+		}
+	}
+}
+
+func TestDescribeRouteTLS(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		notAfter time.Time
+		expected string
+		warning  bool
+	}{
+		"valid":    {notAfter: now.Add(90 * 24 * time.Hour)},
+		"expiring": {notAfter: now.Add(10 * 24 * time.Hour), expected: "expires in", warning: true},
+		"expired":  {notAfter: now.Add(-time.Hour), expected: "has expired", warning: true},
+	}
+	for name, test := range tests {
+		tls := &routeapi.TLSConfig{
+			Termination: routeapi.TLSTerminationEdge,
+			Certificate: testCertificate(t, test.notAfter),
+		}
+		out, _ := tabbedString(func(out *tabwriter.Writer) error {
+			describeRouteTLS(tls, now, out)
+			return nil
+		})
+		if !strings.Contains(out, "edge") || !strings.Contains(out, "inline") {
+			t.Errorf("%s: expected termination and certificate in output: %s", name, out)
+		}
+		if strings.Contains(out, "Warning") != test.warning || !strings.Contains(out, test.expected) {
+			t.Errorf("%s: unexpected warning in output: %s", name, out)
+		}
+	}
+}
+
+// testCertificate returns a PEM encoded self-signed certificate that expires at notAfter
+func testCertificate(t *testing.T, notAfter time.Time) string {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "www.example.com"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestConflictingRoutes(t *testing.T) {
+	route := &routeapi.Route{ObjectMeta: kapi.ObjectMeta{Name: "app"}, Host: "www.example.com", Path: "/app"}
+	routes := []routeapi.Route{
+		*route,
+		{ObjectMeta: kapi.ObjectMeta{Name: "root"}, Host: "www.example.com"},
+		{ObjectMeta: kapi.ObjectMeta{Name: "nested"}, Host: "WWW.example.com", Path: "/app/api"},
+		{ObjectMeta: kapi.ObjectMeta{Name: "sibling"}, Host: "www.example.com", Path: "/other"},
+		{ObjectMeta: kapi.ObjectMeta{Name: "elsewhere"}, Host: "other.example.com", Path: "/app"},
+	}
+	conflicts := conflictingRoutes(route, routes)
+	names := []string{}
+	for _, r := range conflicts {
+		names = append(names, r.Name)
+	}
+	if strings.Join(names, ",") != "root,nested" {
+		t.Errorf("unexpected conflicts: %v", names)
+	}
+}
+
 func TestDeploymentConfigDescriber(t *testing.T) {
 	config := deployapitest.OkDeploymentConfig(1)
 	deployment, _ := deployutil.MakeDeployment(config, kapi.Codec)
@@ -77,32 +635,84 @@ func TestDeploymentConfigDescriber(t *testing.T) {
 			getDeploymentFunc: func(namespace, name string) (*kapi.ReplicationController, error) {
 				return deployment, nil
 			},
+			listDeploymentsFunc: func(namespace string, selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+				return &kapi.ReplicationControllerList{Items: []kapi.ReplicationController{*deployment}}, nil
+			},
 			listPodsFunc: func(namespace string, selector labels.Selector) (*kapi.PodList, error) {
 				return podList, nil
 			},
 		},
 	}
 
-	describe := func() {
-		if output, err := d.Describe("test", "deployment"); err != nil {
+	describe := func() string {
+		output, err := d.Describe("test", "deployment")
+		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
-		} else {
-			t.Logf("describer output:\n%s\n", output)
 		}
+		t.Logf("describer output:\n%s\n", output)
+		return output
 	}
 
 	podList.Items = []kapi.Pod{*mkPod(kapi.PodRunning, 0)}
-	describe()
+	if out := describe(); !strings.Contains(out, "Deployment History:") || !strings.Contains(out, deployment.Name) {
+		t.Errorf("expected deployment history in output: %s", out)
+	}
+
+	deployments := []kapi.ReplicationController{}
+	for i := 1; i <= 3; i++ {
+		past, _ := deployutil.MakeDeployment(deployapitest.OkDeploymentConfig(i), kapi.Codec)
+		deployments = append(deployments, *past)
+	}
+	d.client.(*genericDeploymentDescriberClient).listDeploymentsFunc = func(namespace string, selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+		return &kapi.ReplicationControllerList{Items: deployments}, nil
+	}
+	d.MaxDeployments = 2
+	out := describe()
+	if !strings.Contains(out, deployments[2].Name) || !strings.Contains(out, deployments[1].Name) || strings.Contains(out, deployments[0].Name+"\t") {
+		t.Errorf("expected the two newest deployments in output: %s", out)
+	}
+	if strings.Index(out, deployments[2].Name) > strings.Index(out, deployments[1].Name) {
+		t.Errorf("expected deployments newest first: %s", out)
+	}
+	d.client.(*genericDeploymentDescriberClient).listDeploymentsFunc = func(namespace string, selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+		return &kapi.ReplicationControllerList{}, nil
+	}
+	if out := describe(); !strings.Contains(out, "No deployments.") {
+		t.Errorf("expected no deployments in output: %s", out)
+	}
+
+	readyPod := mkPod(kapi.PodRunning, 0)
+	readyPod.Status.Conditions = []kapi.PodCondition{{Kind: kapi.PodReady, Status: kapi.ConditionFull}}
+	podList.Items = append(podList.Items, *readyPod)
+	if out := describe(); !strings.Contains(out, fmt.Sprintf("1/%d ready", deployment.Spec.Replicas)) {
+		t.Errorf("expected ready pod count in output: %s", out)
+	}
 
 	config.Triggers = append(config.Triggers, deployapitest.OkConfigChangeTrigger())
 	describe()
 
+	if out := describe(); strings.Contains(out, "- Command:") {
+		t.Errorf("unexpected custom strategy parameters in output: %s", out)
+	}
+
 	config.Template.Strategy = deployapitest.OkCustomStrategy()
-	describe()
+	if out := describe(); !strings.Contains(out, "openshift/origin-deployer") || !strings.Contains(out, "ENV1=VAL1") || !strings.Contains(out, "/bin/echo hello world") {
+		t.Errorf("expected custom strategy image, environment and command in output: %s", out)
+	}
 
 	config.Triggers[0].ImageChangeParams.RepositoryName = ""
 	config.Triggers[0].ImageChangeParams.From = kapi.ObjectReference{Name: "imageRepo"}
 	describe()
+
+	if out := describe(); strings.Contains(out, "Security Context:") {
+		t.Errorf("unexpected security context for unprivileged containers: %s", out)
+	}
+	container := &config.Template.ControllerTemplate.Template.Spec.Containers[0]
+	container.Privileged = true
+	container.Capabilities.Add = []kapi.CapabilityType{"NET_ADMIN"}
+	if out := describe(); !strings.Contains(out, "Security Context:") || !strings.Contains(out, "NET_ADMIN") {
+		t.Errorf("expected security context in output: %s", out)
+	}
 }
 
 func mkPod(status kapi.PodPhase, exitCode int) *kapi.Pod {
@@ -120,3 +730,137 @@ func mkPod(status kapi.PodPhase, exitCode int) *kapi.Pod {
 		},
 	}
 }
+
+func TestParameterSummary(t *testing.T) {
+	params := []templateapi.Parameter{
+		{Name: "REQUIRED"},
+		{Name: "DEFAULT", Value: "value"},
+		{Name: "GENERATED", Generate: "expression", From: "[a-z]{8}"},
+		{Name: "OVERRIDDEN", Value: "value", Generate: "expression", From: "[a-z]{8}"},
+	}
+	required, defaulted, generated := ParameterSummary(params)
+	if required != 1 || defaulted != 2 || generated != 1 {
+		t.Errorf("unexpected summary: %d required, %d with defaults, %d generated", required, defaulted, generated)
+	}
+
+	d := &TemplateDescriber{}
+	out, _ := tabbedString(func(out *tabwriter.Writer) error {
+		d.DescribeParameters(params, out)
+		return nil
+	})
+	if !strings.Contains(out, "1 required, 2 with defaults, 1 generated") || strings.Index(out, "Summary") > strings.Index(out, "REQUIRED") {
+		t.Errorf("expected the summary before the parameters: %s", out)
+	}
+}
+
+func TestDescribeParameterWarnings(t *testing.T) {
+	d := &TemplateDescriber{ValidateParameters: true}
+	params := []templateapi.Parameter{{Name: "NAME"}, {Name: "UNUSED"}}
+	objects := []runtime.Object{
+		&kapi.Service{ObjectMeta: kapi.ObjectMeta{Name: "${NAME}", Labels: map[string]string{"tier": "${TIER}"}}},
+	}
+	out, _ := tabbedString(func(out *tabwriter.Writer) error {
+		d.DescribeParameterWarnings(params, objects, out)
+		return nil
+	})
+	for _, expected := range []string{"Warnings:", `"TIER" is referenced but not declared`, `"UNUSED" is declared but never referenced`} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output: %s", expected, out)
+		}
+	}
+	if strings.Contains(out, `"NAME"`) {
+		t.Errorf("unexpected warning for a declared and referenced parameter: %s", out)
+	}
+
+	out, _ = tabbedString(func(out *tabwriter.Writer) error {
+		d.DescribeParameterWarnings([]templateapi.Parameter{{Name: "NAME"}, {Name: "TIER"}}, objects, out)
+		return nil
+	})
+	if len(out) > 0 {
+		t.Errorf("unexpected warnings: %s", out)
+	}
+}
+
+func TestDescribeTemplateObjectTypes(t *testing.T) {
+	d := &TemplateDescriber{ObjectTyper: kapi.Scheme}
+	objects := []runtime.Object{
+		&kapi.Service{ObjectMeta: kapi.ObjectMeta{Name: "frontend"}},
+		&kapi.Service{ObjectMeta: kapi.ObjectMeta{Name: "database"}},
+		&deployapi.DeploymentConfig{ObjectMeta: kapi.ObjectMeta{Name: "frontend"}},
+		&runtime.Unknown{RawJSON: []byte(`{"kind":"Widget","apiVersion":"v1alpha1"}`)},
+	}
+	declared := decodeObjectTypes([]byte(`{"kind":"Template","items":[
+		{"kind":"Service","apiVersion":"v1beta1","id":"frontend"},
+		{"kind":"Service","apiVersion":"v1beta3","metadata":{"name":"database"}},
+		{"kind":"DeploymentConfig","apiVersion":"v1beta1","id":"frontend"},
+		{"kind":"Widget","apiVersion":"v1alpha1"}
+	]}`))
+	if len(declared) != 4 {
+		t.Fatalf("expected the declared type of each item, got %#v", declared)
+	}
+	out, err := tabbedString(func(out *tabwriter.Writer) error {
+		d.DescribeObjectTypes(objects, declared, out)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{"v1beta1\tService\t1", "v1beta3\tService\t1", "v1beta1\tDeploymentConfig\t1", "v1alpha1\tWidget\t(unrecognized)\t1"} {
+		if !strings.Contains(strings.Join(strings.Fields(out), "\t"), expected) {
+			t.Errorf("expected %q in output: %s", expected, out)
+		}
+	}
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 6 {
+		t.Errorf("expected a header and one row per type, got: %s", out)
+	}
+
+	// without declared versions, the version the client encodes objects with is shown
+	out, _ = tabbedString(func(out *tabwriter.Writer) error {
+		d.DescribeObjectTypes(objects, nil, out)
+		return nil
+	})
+	if !strings.Contains(strings.Join(strings.Fields(out), "\t"), latest.Version+"\tService\t2") {
+		t.Errorf("expected services with the client version: %s", out)
+	}
+}
+
+func TestDescribeTemplateObjectLabels(t *testing.T) {
+	d := &TemplateDescriber{MetadataAccessor: meta.NewAccessor(), ObjectTyper: kapi.Scheme}
+	objects := []runtime.Object{&kapi.Service{ObjectMeta: kapi.ObjectMeta{Name: "frontend"}}}
+	out, err := tabbedString(func(out *tabwriter.Writer) error {
+		d.DescribeObjects(objects, map[string]string{"template": "ruby"}, out)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Applied to all objects:") || !strings.Contains(out, "template=ruby") {
+		t.Errorf("expected the template labels to be described as applied to all objects: %s", out)
+	}
+	if strings.Contains(out, "Common Labels") {
+		t.Errorf("unexpected Common Labels in output: %s", out)
+	}
+}
+
+func TestDescribeTemplateDeclaredVersions(t *testing.T) {
+	template := `{"kind":"Template","apiVersion":"v1beta1","metadata":{"name":"app","namespace":"default"},"items":[
+		{"kind":"Service","apiVersion":"v1beta3","metadata":{"name":"frontend"},"spec":{"ports":[{"port":80}]}}
+	]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(template))
+	}))
+	defer server.Close()
+	c, err := client.New(&kclient.Config{Host: server.URL, Version: latest.Version})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := &TemplateDescriber{c, meta.NewAccessor(), kapi.Scheme, nil, false}
+	out, err := d.Describe("default", "app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(strings.Join(strings.Fields(out), "\t"), "v1beta3\tService\t1") {
+		t.Errorf("expected the declared version of the service: %s", out)
+	}
+}
@@ -3,7 +3,9 @@ package describe
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
@@ -16,9 +18,8 @@ const emptyString = "<none>"
 
 func tabbedString(f func(*tabwriter.Writer) error) (string, error) {
 	out := new(tabwriter.Writer)
-	b := make([]byte, 1024)
-	buf := bytes.NewBuffer(b)
-	out.Init(buf, 0, 8, 1, '\t', 0)
+	buf := &bytes.Buffer{}
+	out.Init(buf, 0, 8, 1, '\t', tabwriter.StripEscape)
 
 	err := f(out)
 	if err != nil {
@@ -30,6 +31,31 @@ func tabbedString(f func(*tabwriter.Writer) error) (string, error) {
 	return str, nil
 }
 
+// tabbedSection writes the output of f to out as a nested section with its own
+// column widths, flushed independently of the columns of out. Each line of the
+// section is prefixed with indent.
+func tabbedSection(out *tabwriter.Writer, indent string, f func(*tabwriter.Writer) error) error {
+	section, err := tabbedString(f)
+	if err != nil {
+		return err
+	}
+	section = strings.TrimRight(section, "\n")
+	if len(section) == 0 {
+		return nil
+	}
+	lines := strings.Split(section, "\n")
+	for i := range lines {
+		lines[i] = indent + lines[i]
+	}
+	// escaped text is passed through out without being split into its columns
+	escape := []byte{tabwriter.Escape}
+	out.Write(escape)
+	out.Write([]byte(strings.Join(lines, "\n")))
+	out.Write(escape)
+	out.Write([]byte("\n"))
+	return nil
+}
+
 func toString(v interface{}) string {
 	value := fmt.Sprintf("%s", v)
 	if len(value) == 0 {
@@ -51,7 +77,28 @@ func convertEnv(env []api.EnvVar) map[string]string {
 }
 
 func formatString(out *tabwriter.Writer, label string, v interface{}) {
-	fmt.Fprintf(out, fmt.Sprintf("%s:\t%s\n", label, toString(v)))
+	fmt.Fprintf(out, "%s:\t%s\n", label, sanitizeValue(toString(v)))
+}
+
+// sanitizeValue replaces the tabs in a value with spaces and its line breaks
+// with a visible \n, so that the value does not break the output columns
+func sanitizeValue(value string) string {
+	value = strings.Replace(value, "\r\n", "\n", -1)
+	value = strings.Replace(value, "\t", " ", -1)
+	return strings.Replace(value, "\n", `\n`, -1)
+}
+
+// formatAge returns a short, human readable form of an age, such as "5m"
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(age.Hours()/24))
 }
 
 func formatLabels(labelMap map[string]string) string {
@@ -90,6 +137,9 @@ func formatMeta(out *tabwriter.Writer, m api.ObjectMeta) {
 	formatAnnotations(out, m, "")
 }
 
+// webhookURLUnavailable is shown instead of a webhook URL when the master host is not known
+const webhookURLUnavailable = "<webhook URL unavailable: master host unknown>"
+
 // webhookURL assembles map with of webhook type as key and webhook url and value
 func webhookURL(c *buildapi.BuildConfig, configHost string) map[string]string {
 	result := map[string]string{}
@@ -104,13 +154,13 @@ func webhookURL(c *buildapi.BuildConfig, configHost string) map[string]string {
 		if len(whTrigger) == 0 {
 			continue
 		}
-		apiVersion := latest.Version
-		host := "localhost"
-		if len(configHost) > 0 {
-			host = configHost
+		if len(configHost) == 0 {
+			result[string(trigger.Type)] = webhookURLUnavailable
+			continue
 		}
+		apiVersion := latest.Version
 		url := fmt.Sprintf("%s/osapi/%s/buildConfigHooks/%s/%s/%s",
-			host,
+			configHost,
 			apiVersion,
 			c.Name,
 			whTrigger,
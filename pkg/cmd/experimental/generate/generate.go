@@ -1,27 +1,47 @@
 package generate
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
 	kcmdutil "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/errors"
 	"github.com/fsouza/go-dockerclient"
+	"github.com/ghodss/yaml"
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/origin/pkg/api/latest"
+	buildapi "github.com/openshift/origin/pkg/build/api"
 	osclient "github.com/openshift/origin/pkg/client"
 	cmdutil "github.com/openshift/origin/pkg/cmd/util"
 	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
 	dh "github.com/openshift/origin/pkg/cmd/util/docker"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	"github.com/openshift/origin/pkg/dockerregistry"
 	genapp "github.com/openshift/origin/pkg/generate/app"
 	gen "github.com/openshift/origin/pkg/generate/generator"
+	"github.com/openshift/origin/pkg/generate/git"
 	"github.com/openshift/origin/pkg/generate/source"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+	"github.com/openshift/origin/pkg/version"
 )
 
 const longDescription = `
@@ -30,25 +50,37 @@ Experimental command
 Generate configuration to build and deploy code in OpenShift from a source code
 repository.
 
-Docker builds - If a Dockerfile is present in the source code repository, then
-a docker build is generated.
+Docker builds - If a Dockerfile is present in the source code repository, or in
+the directory set with --context-dir, then a docker build is generated. Use
+--docker-context to choose the directory of the Dockerfile to build.
 
 STI builds - If no builder image is specified as an argument, generate will detect
-the type of source repository (JEE, Ruby, NodeJS) and associate a default builder
+the type of source repository (JEE, Ruby, NodeJS, Python) and associate a default builder
 to it.
 
-Services and Exposed Port - For Docker builds, generate looks for EXPOSE directives
-in the Dockerfile to determine which port to expose. For STI builds, generate will
-use the exposed port of the builder image. In either case, if a different port
-needs to be exposed, use the --port flag to specify them. Services will be
-generated using this port as well.
+The build strategy is chosen in this order: --builder-image forces an STI build,
+then --docker-context forces a Docker build, then a detected Dockerfile, and
+finally the detected source language. Use --strategy=docker or --strategy=sti
+to skip detection of the other strategy.
+
+Services and Exposed Ports - For Docker builds, generate looks for EXPOSE directives
+in the Dockerfile to determine which ports to expose. For STI builds, generate will
+use the exposed ports of the builder image. In either case, if different ports
+need to be exposed, use the --port flag to specify them. A service will be
+generated for each exposed port. Use --expose to also generate a route to the
+service of the first port.
+
+Templates - If a local source directory contains a single template in
+.openshift/templates, the template is processed instead and its objects are
+returned. Template parameters may be set with --param.
 
 
 Usage:
-openshift ex generate [source]
+openshift ex generate [source...]
 
 The source parameter may be a directory or a repository URL.
-If not specified, the current directory is used.
+If not specified, the current directory is used. When more than one source is
+provided, the objects generated for each source are returned in a single list.
 
 Examples:
 
@@ -61,8 +93,56 @@ Examples:
     # Use a remote git repository
     $ openshift ex generate https://github.com/openshift/ruby-hello-world.git
 
+    # Detect and build the application in the web directory of the repository
+    $ openshift ex generate --context-dir=web
+
     # Force the application to use the specific builder-image
     $ openshift ex generate --builder-image=openshift/ruby-20-centos
+
+    # Build the source with STI even though it contains a Dockerfile
+    $ openshift ex generate --strategy=sti
+
+    # Make the application reachable at www.example.com
+    $ openshift ex generate --expose --hostname=www.example.com
+
+    # Send an extra HTTP header when cloning a remote repository behind an auth proxy
+    $ openshift ex generate https://git.example.com/app.git --git-http-header="X-Auth-Token: abc"
+
+    # Process the template in .openshift/templates of the current directory with a parameter value
+    $ openshift ex generate --param=ADMIN_PASSWORD=secret
+
+    # Push the built image to the frontend repository of the shared project
+    $ openshift ex generate --to-image=shared/frontend:v1
+
+    # Set the environment of the deployment from a file, overriding one of its values
+    $ openshift ex generate --env-file=app.env -e LOG_LEVEL=debug
+
+    # Label all generated objects so they can be selected together
+    $ openshift ex generate --labels=app=frontend
+
+    # Annotate all generated objects
+    $ openshift ex generate --annotations=description=frontend,owner=web-team
+
+    # Generate objects for two services, exposing a different port for each
+    $ openshift ex generate ./frontend ./backend --port=frontend=8080 --port=backend=9090
+
+    # Generate a plain deployment that is not redeployed when the build output changes
+    $ openshift ex generate --deployment-config=false
+
+    # Show the detected build strategy and builder image without generating objects
+    $ openshift ex generate --dry-run
+
+    # Generate YAML instead of JSON
+    $ openshift ex generate -o yaml
+
+    # Generate a template whose parameters set the environment of the deployment
+    $ openshift ex generate --as-template=frontend
+
+    # Limit the CPU and memory of the deployed containers
+    $ openshift ex generate --limits=cpu=500m,memory=256Mi
+
+    # Merge the generated objects into an existing manifest
+    $ openshift ex generate --merge-into=base.yaml
 `
 
 type params struct {
@@ -71,9 +151,38 @@ type params struct {
 	sourceRef,
 	sourceURL,
 	dockerContext,
+	contextDir,
 	builderImage,
-	port string
-	env cmdutil.Environment
+	strategy,
+	envFile string
+	ports              []string
+	portArgs           util.StringList
+	env                cmdutil.Environment
+	envArgs            []string
+	gitHTTPHeaders     util.StringList
+	templateParams     util.StringList
+	maxRetries         int
+	mergeInto          string
+	templateName       string
+	preStopExec        string
+	limits             kapi.ResourceList
+	timeout            time.Duration
+	stop               <-chan struct{}
+	annotations        map[string]string
+	labels             map[string]string
+	toImage            *imageTarget
+	affinity           string
+	serviceType        string
+	hostname           string
+	expose             bool
+	credentials        string
+	insecure           util.StringList
+	provenance         bool
+	incremental        bool
+	deployConfig       bool
+	output             string
+	dryRun             bool
+	allowMissingImages bool
 }
 
 func NewCmdGenerate(f *clientcmd.Factory, parentName, name string) *cobra.Command {
@@ -85,13 +194,25 @@ func NewCmdGenerate(f *clientcmd.Factory, parentName, name string) *cobra.Comman
 		Short: "Generates an application configuration from a source repository",
 		Long:  longDescription,
 		Run: func(c *cobra.Command, args []string) {
-			osClient, _, err := f.Clients(c)
+			namespace, err := f.DefaultNamespace(c)
 			if err != nil {
-				osClient = nil
+				namespace = ""
 			}
-			dockerClient, _, err := dockerHelper.GetClient()
+			client, _, osErr := f.Clients(c)
+			dockerClient, _, dockerErr := dockerHelper.GetClient()
+			serverAvailable, dockerAvailable, err := checkImageSources(namespace, client, osErr, dockerClient, dockerErr, input.allowMissingImages)
 			if err != nil {
-				osClient = nil
+				exitWithError(err)
+			}
+			var osClient osclient.Interface
+			if serverAvailable {
+				osClient = client
+			}
+			if !dockerAvailable {
+				dockerClient = nil
+			}
+			if len(args) > 1 && len(input.sourceURL) > 0 {
+				exitWithError(fmt.Errorf("--source-url may not be used with more than one source"))
 			}
 			if len(args) == 1 {
 				if genapp.IsRemoteRepository(args[0]) {
@@ -100,26 +221,84 @@ func NewCmdGenerate(f *clientcmd.Factory, parentName, name string) *cobra.Comman
 					input.sourceDir = args[0]
 				}
 			}
-			if len(input.sourceDir) == 0 && len(input.sourceURL) == 0 {
+			if len(args) <= 1 && len(input.sourceDir) == 0 && len(input.sourceURL) == 0 {
 				if input.sourceDir, err = os.Getwd(); err != nil {
 					exitWithError(err)
 				}
 			}
 			if envParam := kcmdutil.GetFlagString(c, "environment"); len(envParam) > 0 {
-				envVars := strings.Split(envParam, ",")
-				env, _, errs := cmdutil.ParseEnvironmentArguments(envVars)
-				if len(errs) > 0 {
-					exitWithError(errors.NewAggregate(errs))
+				input.envArgs = strings.Split(envParam, ",")
+			}
+			if len(input.envFile) > 0 {
+				fileEnv, err := readEnvFile(input.envFile)
+				if err != nil {
+					exitWithError(err)
 				}
-				input.env = env
+				// later values override earlier ones, so values set with -e take precedence
+				input.envArgs = append(fileEnv, input.envArgs...)
 			}
-			namespace, err := f.DefaultNamespace(c)
+			if annotationParam := kcmdutil.GetFlagString(c, "annotations"); len(annotationParam) > 0 {
+				annotations, err := parseAnnotations(strings.Split(annotationParam, ","))
+				if err != nil {
+					exitWithError(err)
+				}
+				input.annotations = annotations
+			}
+			if limitParam := kcmdutil.GetFlagString(c, "limits"); len(limitParam) > 0 {
+				limits, err := parseResourceLimits(strings.Split(limitParam, ","))
+				if err != nil {
+					exitWithError(err)
+				}
+				input.limits = limits
+			}
+			if input.output != "json" && input.output != "yaml" {
+				exitWithError(fmt.Errorf("--output must be json or yaml, got %q", input.output))
+			}
+			input.labels = map[string]string{generatedByLabel: generatedByValue}
+			if labelParam := kcmdutil.GetFlagString(c, "labels"); len(labelParam) > 0 {
+				labels, err := parseLabels(strings.Split(labelParam, ","))
+				if err != nil {
+					exitWithError(err)
+				}
+				for k, v := range labels {
+					input.labels[k] = v
+				}
+			}
+			if toImage := kcmdutil.GetFlagString(c, "to-image"); len(toImage) > 0 {
+				target, err := parseImageTarget(toImage)
+				if err != nil {
+					exitWithError(err)
+				}
+				input.toImage = target
+			}
+			if err := validateSessionAffinity(input.affinity); err != nil {
+				exitWithError(err)
+			}
+			if err := validateServiceType(input.serviceType); err != nil {
+				exitWithError(err)
+			}
+			if len(input.hostname) > 0 && !input.expose {
+				exitWithError(fmt.Errorf("--hostname may only be used with --expose"))
+			}
+			if err := validateHTTPHeaders(input.gitHTTPHeaders); err != nil {
+				exitWithError(err)
+			}
+			registryClient, err := dockerregistry.NewClientWithCredentials(input.credentials, input.insecure)
 			if err != nil {
-				namespace = ""
+				exitWithError(err)
 			}
-			imageResolver := newImageResolver(namespace, osClient, dockerClient)
+			imageResolver := newImageResolver(namespace, osClient, dockerClient, registryClient, input.maxRetries)
 
-			if err = generateApp(input, imageResolver, os.Stdout); err != nil {
+			// buffer the output so that nothing is written if generation times out
+			output := &bytes.Buffer{}
+			if err = withTimeout(input.timeout, func(stop <-chan struct{}) error {
+				generateInput := input
+				generateInput.stop = stop
+				return generateApps(generateInput, args, imageResolver, output)
+			}); err != nil {
+				exitWithError(err)
+			}
+			if _, err = io.Copy(os.Stdout, output); err != nil {
 				exitWithError(err)
 			}
 		},
@@ -127,17 +306,125 @@ func NewCmdGenerate(f *clientcmd.Factory, parentName, name string) *cobra.Comman
 
 	flag := c.Flags()
 	flag.StringVar(&input.name, "name", "", "Set name to use for generated application artifacts")
+	flag.StringVarP(&input.output, "output", "o", "json", "Output format of the generated objects, json or yaml")
+	flag.BoolVar(&input.allowMissingImages, "allow-missing-images", false, "Generate even when neither an OpenShift server nor a local Docker daemon can be reached to look up images")
+	flag.BoolVar(&input.dryRun, "dry-run", false, "Print the detected source, build strategy and exposed ports instead of generating objects")
 	flag.StringVar(&input.sourceRef, "ref", "", "Set the name of the repository branch/ref to use")
 	flag.StringVar(&input.sourceURL, "source-url", "", "Set the source URL")
 	flag.StringVar(&input.dockerContext, "docker-context", "", "Context path for Dockerfile if creating a Docker build")
+	flag.StringVar(&input.contextDir, "context-dir", "", "Subdirectory of the repository that contains the application. The build strategy is detected in this directory and builds use it as their context. A --docker-context is relative to it.")
+	flag.StringVar(&input.strategy, "strategy", autoStrategy, fmt.Sprintf("Build strategy to use, %q, %q or %q. With %q a Dockerfile must be present, and with %q the source language is detected unless --builder-image is set.", autoStrategy, dockerStrategy, stiStrategy, dockerStrategy, stiStrategy))
+	flag.BoolVar(&input.incremental, "incremental", false, "Enable incremental builds for STI build configs, reusing artifacts of the previous build. Has no effect on Docker builds.")
 	flag.StringVar(&input.builderImage, "builder-image", "", "Image to use for STI build")
-	flag.StringVarP(&input.port, "port", "p", "", "Port to expose on pod deployment")
-	flag.StringP("environment", "e", "", "Comma-separated list of environment variables to add to the deployment. Should be in the form of var1=value1,var2=value2,...")
+	flag.VarP(&input.portArgs, "port", "p", "Comma-separated list of ports to expose on pod deployment. A service is generated for each port. When generating from more than one source, use name=port to set a port of a single source.")
+	flag.StringP("environment", "e", "", "Comma-separated list of environment variables to add to the deployment. Should be in the form of var1=value1,var2=value2,... When generating from more than one source, use name=var1=value1 to set a variable for a single source.")
+	flag.StringVar(&input.envFile, "env-file", "", "File of environment variables to add to the deployment, one var=value per line. Blank lines and lines starting with # are ignored. Values set with --environment take precedence.")
+	flag.String("to-image", "", "Image repository the build pushes to, in the form [namespace/]name[:tag]. Defaults to a repository named after the source.")
+	flag.String("labels", "", fmt.Sprintf("Comma-separated list of labels to add to all generated objects. Should be in the form of key1=value1,key2=value2,... A %s=%s label is added unless it is set here.", generatedByLabel, generatedByValue))
+	flag.String("annotations", "", "Comma-separated list of annotations to add to all generated objects. Should be in the form of key1=value1,key2=value2,...")
+	flag.StringVar(&input.affinity, "session-affinity", "", fmt.Sprintf("Session affinity of the generated services, %q or %q", kapi.AffinityTypeClientIP, kapi.AffinityTypeNone))
+	flag.StringVar(&input.serviceType, "service-type", clusterIPServiceType, fmt.Sprintf("Type of the generated services, %q or %q. A %s service requests an external load balancer.", clusterIPServiceType, loadBalancerServiceType, loadBalancerServiceType))
+	flag.BoolVar(&input.expose, "expose", false, "Generate a route to the service of the first exposed port so the application is reachable from outside the cluster")
+	flag.StringVar(&input.hostname, "hostname", "", "Host name of the route generated with --expose. If not set, the host is left for the router to assign.")
+	flag.StringVar(&input.credentials, "registry-credentials-file", "", "Docker config file with the credentials to use when looking up images in a Docker registry. Defaults to ~/.dockercfg.")
+	flag.Var(&input.insecure, "insecure-registry", "Docker registry whose certificate is not verified, and that may be reached over HTTP, when looking up image metadata during generation. May be repeated. Does not affect how the generated builds and deployments pull images.")
+	flag.BoolVar(&input.provenance, "record-provenance", false, "Annotate generated objects with the source, builder image and version of the command used to generate them")
+	flag.Var(&input.gitHTTPHeaders, "git-http-header", "HTTP header in the form \"Name: Value\" to send when cloning a remote repository. May be repeated. Headers are passed to git in its environment, which requires git 2.31 or newer, and are not stored in the generated build configuration.")
+	flag.Var(&input.templateParams, "param", "Template parameter in the form NAME=VALUE, used when the source contains a template in .openshift/templates. May be repeated.")
+	flag.DurationVar(&input.timeout, "timeout", 30*time.Second, "Maximum time to spend cloning remote sources and looking up images before failing. Zero means no timeout.")
+	flag.IntVar(&input.maxRetries, "max-retries", 3, "Number of times to retry image lookups against a server that could not be reached")
+	flag.StringVar(&input.templateName, "as-template", "", "Name of a template to return the generated objects in instead of a list. The container environment variables become template parameters.")
+	flag.StringVar(&input.mergeInto, "merge-into", "", "Path to a multi-document YAML manifest to merge the generated objects into. Generated objects replace manifest objects with the same kind and name.")
+	flag.BoolVar(&input.deployConfig, "deployment-config", true, "Generate a deployment config that redeploys the application when the build pushes a new image. If false, a single deployment without triggers is generated instead.")
+	flag.String("limits", "", fmt.Sprintf("Comma-separated list of resource limits of the deployed containers, such as %s=500m,%s=256Mi", kapi.ResourceCPU, kapi.ResourceMemory))
+	flag.StringVar(&input.preStopExec, "prestop-exec", "", "Shell command to run in the deployed container before it is stopped")
 	dockerHelper.InstallFlags(flag)
 	return c
 }
 
-func newImageResolver(namespace string, osClient osclient.Interface, dockerClient *docker.Client) genapp.Resolver {
+// stopGracePeriod is how long withTimeout waits for fn to return after closing stop
+const stopGracePeriod = 5 * time.Second
+
+// withTimeout runs fn and returns its error, or a timeout error if fn does not
+// return within timeout. When the timeout expires the stop channel passed to fn
+// is closed, so that commands it started are killed, and fn is given
+// stopGracePeriod to return. A timeout of zero waits for fn to return.
+func withTimeout(timeout time.Duration, fn func(stop <-chan struct{}) error) error {
+	if timeout <= 0 {
+		return fn(nil)
+	}
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(stop)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		close(stop)
+		select {
+		case <-done:
+		case <-time.After(stopGracePeriod):
+		}
+		return fmt.Errorf("generation did not complete within %v, use --timeout to wait longer", timeout)
+	}
+}
+
+// resolverBackoff is the wait before the first retry of a failed image lookup
+const resolverBackoff = 500 * time.Millisecond
+
+// dockerPinger is the part of a Docker client used to check that the daemon can be reached
+type dockerPinger interface {
+	Ping() error
+}
+
+// checkImageSources verifies that the OpenShift server and the local Docker daemon
+// can be reached, since creating their clients does not contact them. The server
+// is checked with a cheap request, listing the image repositories of namespace,
+// and is unavailable only if the request could not connect or timed out. Any
+// response from the server, including an error status such as 403 Forbidden,
+// means it is up. The server is not checked when namespace is empty. osErr and
+// dockerErr are the errors from creating the clients, if any. If neither can be
+// reached an error is returned, unless allowMissing is set, in which case a
+// warning is logged.
+func checkImageSources(namespace string, server osclient.ImageRepositoriesNamespacer, osErr error, docker dockerPinger, dockerErr error, allowMissing bool) (serverAvailable, dockerAvailable bool, err error) {
+	if osErr == nil && len(namespace) > 0 {
+		if _, listErr := server.ImageRepositories(namespace).List(labels.Everything(), labels.Everything()); isUnreachable(listErr) {
+			osErr = listErr
+		} else if listErr != nil {
+			glog.V(4).Infof("The OpenShift server responded to the availability check with an error: %v", listErr)
+		}
+	}
+	if dockerErr == nil {
+		dockerErr = docker.Ping()
+	}
+	if osErr != nil {
+		glog.V(2).Infof("Unable to connect to an OpenShift server: %v", osErr)
+	}
+	if dockerErr != nil {
+		glog.V(2).Infof("Unable to connect to a local Docker daemon: %v", dockerErr)
+	}
+	if osErr != nil && dockerErr != nil {
+		if !allowMissing {
+			return false, false, fmt.Errorf("unable to connect to an OpenShift server (%v) or to a local Docker daemon (%v). Images could only be looked up in public Docker registries. Log in to a server or start Docker, or use --allow-missing-images to generate anyway.", osErr, dockerErr)
+		}
+		glog.Warningf("Unable to connect to an OpenShift server or to a local Docker daemon, images will only be looked up in Docker registries")
+	}
+	return osErr == nil, dockerErr == nil, nil
+}
+
+// isUnreachable returns true if the error is a failure to connect to a server or a
+// timeout, rather than an error response from the server
+func isUnreachable(err error) bool {
+	switch err.(type) {
+	case *url.Error, net.Error:
+		return true
+	}
+	return false
+}
+
+func newImageResolver(namespace string, osClient osclient.Interface, dockerClient *docker.Client, registryClient dockerregistry.Client, maxRetries int) genapp.Resolver {
 	resolver := genapp.PerfectMatchWeightedResolver{}
 
 	if dockerClient != nil {
@@ -156,16 +443,16 @@ func newImageResolver(namespace string, osClient osclient.Interface, dockerClien
 			Images:     osClient,
 			Namespaces: namespaces,
 		}
-		resolver = append(resolver, genapp.WeightedResolver{imageStreamResolver, 0.0})
+		resolver = append(resolver, genapp.WeightedResolver{genapp.RetryResolver{Resolver: imageStreamResolver, MaxRetries: maxRetries, Backoff: resolverBackoff}, 0.0})
 	}
 
-	dockerRegistryResolver := &genapp.DockerRegistryResolver{dockerregistry.NewClient()}
-	resolver = append(resolver, genapp.WeightedResolver{dockerRegistryResolver, 0.0})
+	dockerRegistryResolver := &genapp.DockerRegistryResolver{registryClient}
+	resolver = append(resolver, genapp.WeightedResolver{genapp.RetryResolver{Resolver: dockerRegistryResolver, MaxRetries: maxRetries, Backoff: resolverBackoff}, 0.0})
 
 	return resolver
 }
 
-func generateSourceRef(url string, dir string, ref string, name string) (*genapp.SourceRef, error) {
+func generateSourceRef(url string, dir string, ref string, name string, contextDir string) (*genapp.SourceRef, error) {
 	srcRefGen := gen.NewSourceRefGenerator()
 	var result *genapp.SourceRef
 	var err error
@@ -186,73 +473,689 @@ func generateSourceRef(url string, dir string, ref string, name string) (*genapp
 	if len(name) > 0 {
 		result.Name = name
 	}
+	if len(contextDir) > 0 {
+		result.ContextDir = filepath.Clean(contextDir)
+	}
 	return result, nil
 }
 
-func generateBuildStrategyRef(srcRef *genapp.SourceRef, dockerContext string, builderImage string, resolver genapp.Resolver) (*genapp.BuildStrategyRef, error) {
-	strategyRefGen := gen.NewBuildStrategyRefGenerator(source.DefaultDetectors, resolver)
+// Build strategies that may be set with --strategy
+const (
+	autoStrategy   = "auto"
+	dockerStrategy = "docker"
+	stiStrategy    = "sti"
+)
+
+func generateBuildStrategyRef(srcRef *genapp.SourceRef, strategy, dockerContext, builderImage string, gitHTTPHeaders []string, stop <-chan struct{}, resolver genapp.Resolver) (*genapp.BuildStrategyRef, error) {
+	detectStrategy := ""
+	switch strategy {
+	case "", autoStrategy:
+	case dockerStrategy:
+		if len(builderImage) > 0 {
+			return nil, fmt.Errorf("--builder-image may not be used with --strategy=%s", dockerStrategy)
+		}
+		detectStrategy = gen.DockerStrategy
+	case stiStrategy:
+		if len(dockerContext) > 0 {
+			return nil, fmt.Errorf("--docker-context may not be used with --strategy=%s", stiStrategy)
+		}
+		detectStrategy = gen.SourceStrategy
+	default:
+		return nil, fmt.Errorf("unknown build strategy %q, must be %q, %q or %q", strategy, autoStrategy, dockerStrategy, stiStrategy)
+	}
+
+	repository := git.NewRepositoryWithHTTPHeaders(gitHTTPHeaders, stop)
+	strategyRefGen := gen.NewBuildStrategyRefGeneratorForRepository(repository, source.DefaultDetectors, resolver)
 	imageRefGen := gen.NewImageRefGenerator()
-	if len(dockerContext) > 0 {
-		glog.V(3).Infof("Generating build strategy reference using dockerContext: %s", dockerContext)
-		return strategyRefGen.FromSourceRefAndDockerContext(*srcRef, dockerContext)
-	} else if len(builderImage) > 0 {
+	// An explicit builder image takes precedence over an explicit Docker context,
+	// which takes precedence over a detected Dockerfile or source language.
+	if len(builderImage) > 0 {
 		glog.V(3).Infof("Generating build strategy reference using builder image: %s", builderImage)
 		builderRef, err := imageRefGen.FromNameAndResolver(builderImage, resolver)
 		if err != nil {
 			return nil, err
 		}
 		return strategyRefGen.FromSTIBuilderImage(builderRef)
+	} else if len(dockerContext) > 0 {
+		glog.V(3).Infof("Generating build strategy reference using dockerContext: %s", dockerContext)
+		return strategyRefGen.FromSourceRefAndDockerContext(*srcRef, filepath.Join(srcRef.ContextDir, dockerContext))
 	} else {
 		glog.V(3).Infof("Detecting build strategy using source reference: %#v", srcRef)
-		return strategyRefGen.FromSourceRef(*srcRef)
+		return strategyRefGen.FromSourceRefAndStrategy(*srcRef, detectStrategy)
 	}
 }
 
 func generateApp(input params, imageResolver genapp.Resolver, out io.Writer) error {
-	// Get a SourceRef
-	srcRef, err := generateSourceRef(input.sourceURL, input.sourceDir, input.sourceRef, input.name)
+	if input.dryRun {
+		return printDetection(input, imageResolver, out)
+	}
+	objects, err := generateObjects(input, imageResolver)
 	if err != nil {
 		return err
 	}
-	glog.V(2).Infof("Source reference: %#v", srcRef)
-
-	// Get a BuildStrategyRef
-	strategyRef, err := generateBuildStrategyRef(srcRef, input.dockerContext, input.builderImage, imageResolver)
-	if err != nil {
+	if err := addLabels(objects, input.labels); err != nil {
 		return err
 	}
-	glog.V(2).Infof("Generated build strategy reference: %#v", strategyRef)
+	return writeList(&kapi.List{Items: objects}, input.mergeInto, input.templateName, input.output, out)
+}
+
+// generateObjects generates the objects to build and deploy a single source
+func generateObjects(input params, imageResolver genapp.Resolver) ([]runtime.Object, error) {
+	// Process a template from the source directory if one is present
+	if len(input.sourceDir) > 0 {
+		t, found, err := findSourceTemplate(input.sourceDir)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			if conflicts := sourceTemplateConflicts(input); len(conflicts) > 0 {
+				return nil, fmt.Errorf("the source contains a template in %s, which is used as is, so these flags may not be used: %s", sourceTemplateDir, strings.Join(conflicts, ", "))
+			}
+			glog.V(2).Infof("Processing template %q from %s", t.Name, input.sourceDir)
+			list, err := processSourceTemplate(t, input.templateParams)
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		}
+	}
+	if len(input.templateParams) > 0 {
+		return nil, fmt.Errorf("--param may only be used when the source directory contains a template in %s", sourceTemplateDir)
+	}
 
-	if len(input.port) > 0 {
-		strategyRef.Base.Info.Config.ExposedPorts = map[string]struct{}{input.port: {}}
+	srcRef, strategyRef, err := detectSource(input, imageResolver)
+	if err != nil {
+		return nil, err
 	}
 
 	pipeline, err := genapp.NewBuildPipeline(srcRef.Name, strategyRef.Base, strategyRef, srcRef)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if input.toImage != nil {
+		pipeline.Image.Name = input.toImage.name
+		if len(input.toImage.tag) > 0 {
+			pipeline.Image.Tag = input.toImage.tag
+		}
 	}
 	env := genapp.Environment{}
 	for k, v := range input.env {
 		env[k] = v
 	}
 	if err := pipeline.NeedsDeployment(env); err != nil {
-		return err
+		return nil, err
 	}
 
 	objects, err := pipeline.Objects(genapp.NewAcceptFirst())
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if input.toImage != nil {
+		objects = setImageTarget(objects, input.toImage)
 	}
 	objects = genapp.AddServices(objects)
-	list := &kapi.List{Items: objects}
-	output, err := latest.Codec.Encode(list)
+	if input.incremental {
+		if strategyRef.IsDockerBuild {
+			glog.Warningf("--incremental has no effect on Docker builds")
+		}
+		setIncremental(objects)
+	}
+	if len(input.affinity) > 0 {
+		setSessionAffinity(objects, kapi.AffinityType(input.affinity))
+	}
+	if input.serviceType == loadBalancerServiceType {
+		setExternalLoadBalancer(objects)
+	}
+	if input.expose {
+		if objects, err = addRoute(objects, input.hostname); err != nil {
+			return nil, err
+		}
+	}
+	if len(input.preStopExec) > 0 {
+		addPreStopHook(objects, input.preStopExec)
+	}
+	if len(input.limits) > 0 {
+		setResourceLimits(objects, input.limits)
+	}
+	if err := addAnnotations(objects, input.annotations); err != nil {
+		return nil, err
+	}
+	if input.provenance {
+		if err := addAnnotations(objects, provenanceAnnotations(srcRef, strategyRef)); err != nil {
+			return nil, err
+		}
+	}
+	if !input.deployConfig {
+		objects = deploymentsFromConfigs(objects)
+	}
+	return objects, nil
+}
+
+// sourceTemplateConflicts returns the flags that are set but have no effect when
+// the objects are processed from a template in the source directory
+func sourceTemplateConflicts(input params) []string {
+	conflicts := []string{}
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{
+		{"--port", len(input.ports) > 0},
+		{"--environment or --env-file", len(input.env) > 0},
+		{"--strategy", len(input.strategy) > 0 && input.strategy != autoStrategy},
+		{"--builder-image", len(input.builderImage) > 0},
+		{"--docker-context", len(input.dockerContext) > 0},
+		{"--incremental", input.incremental},
+		{"--to-image", input.toImage != nil},
+		{"--session-affinity", len(input.affinity) > 0},
+		{"--service-type", len(input.serviceType) > 0 && input.serviceType != clusterIPServiceType},
+		{"--expose", input.expose},
+		{"--hostname", len(input.hostname) > 0},
+		{"--prestop-exec", len(input.preStopExec) > 0},
+		{"--limits", len(input.limits) > 0},
+		{"--annotations", len(input.annotations) > 0},
+		{"--record-provenance", input.provenance},
+		{"--deployment-config=false", !input.deployConfig},
+	} {
+		if flag.set {
+			conflicts = append(conflicts, flag.name)
+		}
+	}
+	return conflicts
+}
+
+// detectSource resolves the source reference and build strategy of a single
+// source, exposing the ports set with --port if there are any
+func detectSource(input params, imageResolver genapp.Resolver) (*genapp.SourceRef, *genapp.BuildStrategyRef, error) {
+	// Get a SourceRef
+	srcRef, err := generateSourceRef(input.sourceURL, input.sourceDir, input.sourceRef, input.name, input.contextDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	glog.V(2).Infof("Source reference: %#v", srcRef)
+
+	// Get a BuildStrategyRef
+	strategyRef, err := generateBuildStrategyRef(srcRef, input.strategy, input.dockerContext, input.builderImage, input.gitHTTPHeaders, input.stop, imageResolver)
+	if err != nil {
+		return nil, nil, err
+	}
+	glog.V(2).Infof("Generated build strategy reference: %#v", strategyRef)
+
+	if len(input.ports) > 0 {
+		if strategyRef.Base.Info == nil {
+			strategyRef.Base.Info = &imageapi.DockerImage{}
+		}
+		strategyRef.Base.Info.Config.ExposedPorts = map[string]struct{}{}
+		for _, port := range input.ports {
+			strategyRef.Base.Info.Config.ExposedPorts[port] = struct{}{}
+		}
+	}
+	return srcRef, strategyRef, nil
+}
+
+// printDetection prints what was detected about a single source without
+// generating any objects
+func printDetection(input params, imageResolver genapp.Resolver, out io.Writer) error {
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	defer w.Flush()
+	if len(input.sourceDir) > 0 {
+		t, found, err := findSourceTemplate(input.sourceDir)
+		if err != nil {
+			return err
+		}
+		if found {
+			fmt.Fprintf(w, "Template:\t%s\n", t.Name)
+			return nil
+		}
+	}
+	srcRef, strategyRef, err := detectSource(input, imageResolver)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Source URL:\t%s\n", srcRef.URL)
+	fmt.Fprintf(w, "Source Ref:\t%s\n", srcRef.Ref)
+	if len(srcRef.Dir) > 0 {
+		fmt.Fprintf(w, "Source Dir:\t%s\n", srcRef.Dir)
+	}
+	if len(srcRef.ContextDir) > 0 {
+		fmt.Fprintf(w, "Context Dir:\t%s\n", srcRef.ContextDir)
+	}
+	strategy, _ := strategyRef.BuildStrategy()
+	fmt.Fprintf(w, "Strategy:\t%s\n", strategy.Type)
+	if strategyRef.Base != nil {
+		label := "Builder Image"
+		if strategyRef.IsDockerBuild {
+			label = "Base Image"
+		}
+		fmt.Fprintf(w, "%s:\t%s\n", label, strategyRef.Base.NameReference())
+	}
+	platform := strategyRef.Platform
+	if len(platform) == 0 {
+		platform = "<none>"
+	}
+	fmt.Fprintf(w, "Detected Platform:\t%s\n", platform)
+
+	ports := []string{}
+	if strategyRef.Base != nil && strategyRef.Base.Info != nil {
+		for port := range strategyRef.Base.Info.Config.ExposedPorts {
+			ports = append(ports, port)
+		}
+	}
+	sort.Strings(ports)
+	if len(ports) == 0 {
+		ports = append(ports, "<none>")
+	}
+	fmt.Fprintf(w, "Exposed Ports:\t%s\n", strings.Join(ports, ", "))
+	return nil
+}
+
+// parseAnnotations parses key=value pairs into annotations, returning an
+// aggregate error for pairs that are malformed or have an invalid key
+func parseAnnotations(pairs []string) (map[string]string, error) {
+	return parseKeyValues("annotation", pairs)
+}
+
+// parseLabels parses key=value pairs into labels, returning an aggregate
+// error for pairs that are malformed or have an invalid key
+func parseLabels(pairs []string) (map[string]string, error) {
+	return parseKeyValues("label", pairs)
+}
+
+// readEnvFile reads environment variables of the form key=value from a file,
+// one per line, ignoring blank lines and lines that start with #
+func readEnvFile(path string) ([]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	errs := []error{}
+	env := []string{}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, _, lineErrs := cmdutil.ParseEnvironmentArguments([]string{line}); len(lineErrs) > 0 {
+			for _, err := range lineErrs {
+				errs = append(errs, fmt.Errorf("%s:%d: %v", path, i+1, err))
+			}
+			continue
+		}
+		env = append(env, line)
+	}
+	if len(errs) > 0 {
+		return nil, errors.NewAggregate(errs)
+	}
+	return env, nil
+}
+
+// parseKeyValues parses key=value pairs with qualified names as keys
+func parseKeyValues(kind string, pairs []string) (map[string]string, error) {
+	errs := []error{}
+	values := map[string]string{}
+	for _, pair := range pairs {
+		p := strings.SplitN(pair, "=", 2)
+		if len(p) != 2 {
+			errs = append(errs, fmt.Errorf("%ss must be of the form key=value: %s", kind, pair))
+			continue
+		}
+		if !util.IsQualifiedName(p[0]) {
+			errs = append(errs, fmt.Errorf("invalid %s key %q", kind, p[0]))
+			continue
+		}
+		if _, exists := values[p[0]]; exists {
+			errs = append(errs, fmt.Errorf("%s key %q may only be specified once", kind, p[0]))
+			continue
+		}
+		values[p[0]] = p[1]
+	}
+	if len(errs) > 0 {
+		return nil, errors.NewAggregate(errs)
+	}
+	return values, nil
+}
+
+// addLabels sets the provided labels on each generated object
+func addLabels(objects []runtime.Object, labels map[string]string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	for _, obj := range objects {
+		objMeta, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		existing := objMeta.Labels()
+		if existing == nil {
+			existing = map[string]string{}
+		}
+		for k, v := range labels {
+			existing[k] = v
+		}
+		objMeta.SetLabels(existing)
+	}
+	return nil
+}
+
+// addAnnotations sets the provided annotations on each generated object
+func addAnnotations(objects []runtime.Object, annotations map[string]string) error {
+	if len(annotations) == 0 {
+		return nil
+	}
+	for _, obj := range objects {
+		objMeta, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		existing := objMeta.Annotations()
+		if existing == nil {
+			existing = map[string]string{}
+		}
+		for k, v := range annotations {
+			existing[k] = v
+		}
+		objMeta.SetAnnotations(existing)
+	}
+	return nil
+}
+
+// validateSessionAffinity ensures affinity is empty or a supported affinity type
+func validateSessionAffinity(affinity string) error {
+	switch kapi.AffinityType(affinity) {
+	case "", kapi.AffinityTypeClientIP, kapi.AffinityTypeNone:
+		return nil
+	}
+	return fmt.Errorf("session affinity must be %q or %q: %s", kapi.AffinityTypeClientIP, kapi.AffinityTypeNone, affinity)
+}
+
+// setSessionAffinity sets the session affinity of each generated service
+func setSessionAffinity(objects []runtime.Object, affinity kapi.AffinityType) {
+	for _, obj := range objects {
+		if service, ok := obj.(*kapi.Service); ok {
+			service.Spec.SessionAffinity = affinity
+		}
+	}
+}
+
+// Service types that may be set with --service-type
+const (
+	clusterIPServiceType    = "ClusterIP"
+	nodePortServiceType     = "NodePort"
+	loadBalancerServiceType = "LoadBalancer"
+)
+
+// validateServiceType ensures serviceType is a supported service type. Services
+// in this version of Kubernetes cannot be exposed on a node port.
+func validateServiceType(serviceType string) error {
+	switch serviceType {
+	case clusterIPServiceType, loadBalancerServiceType:
+		return nil
+	case nodePortServiceType:
+		return fmt.Errorf("%s services are not supported by this server, use %q or %q", nodePortServiceType, clusterIPServiceType, loadBalancerServiceType)
+	}
+	return fmt.Errorf("service type must be %q or %q: %s", clusterIPServiceType, loadBalancerServiceType, serviceType)
+}
+
+// setExternalLoadBalancer requests an external load balancer for each generated service
+func setExternalLoadBalancer(objects []runtime.Object) {
+	for _, obj := range objects {
+		if service, ok := obj.(*kapi.Service); ok {
+			service.Spec.CreateExternalLoadBalancer = true
+		}
+	}
+}
+
+// addRoute adds a route to the first generated service, which serves the first
+// exposed port. The route host is left empty unless hostname is set.
+func addRoute(objects []runtime.Object, hostname string) ([]runtime.Object, error) {
+	for _, obj := range objects {
+		if service, ok := obj.(*kapi.Service); ok {
+			route := &routeapi.Route{
+				ObjectMeta:  kapi.ObjectMeta{Name: service.Name},
+				Host:        hostname,
+				ServiceName: service.Name,
+			}
+			return append(objects, route), nil
+		}
+	}
+	return nil, fmt.Errorf("--expose requires a service, use --port to set the ports to expose")
+}
+
+// setIncremental enables incremental builds in each generated STI build config
+func setIncremental(objects []runtime.Object) {
+	for _, obj := range objects {
+		if config, ok := obj.(*buildapi.BuildConfig); ok && config.Parameters.Strategy.STIStrategy != nil {
+			config.Parameters.Strategy.STIStrategy.Incremental = true
+		}
+	}
+}
+
+// The label added to generated objects unless --labels sets it
+const (
+	generatedByLabel = "generatedby"
+	generatedByValue = "openshift-generate"
+)
+
+// Annotations recording how an object was generated
+const (
+	generatedByAnnotation           = "openshift.io/generated-by"
+	generatedSourceURLAnnotation    = "openshift.io/generated-source-url"
+	generatedSourceRefAnnotation    = "openshift.io/generated-source-ref"
+	generatedBuilderImageAnnotation = "openshift.io/generated-builder-image"
+)
+
+// provenanceAnnotations describes the source and builder image objects were
+// generated from. Credentials and query parameters in the source URL are not
+// recorded, since they may carry access tokens.
+func provenanceAnnotations(srcRef *genapp.SourceRef, strategyRef *genapp.BuildStrategyRef) map[string]string {
+	annotations := map[string]string{
+		generatedByAnnotation: fmt.Sprintf("openshift ex generate %s", version.Get().String()),
+	}
+	if srcRef.URL != nil {
+		sourceURL := *srcRef.URL
+		sourceURL.User = nil
+		sourceURL.RawQuery = ""
+		annotations[generatedSourceURLAnnotation] = sourceURL.String()
+	}
+	if len(srcRef.Ref) > 0 {
+		annotations[generatedSourceRefAnnotation] = srcRef.Ref
+	}
+	if strategyRef.Base != nil {
+		annotations[generatedBuilderImageAnnotation] = strategyRef.Base.NameReference()
+	}
+	return annotations
+}
+
+// addPreStopHook sets a preStop hook running command in a shell on the containers
+// of each generated deployment config
+func addPreStopHook(objects []runtime.Object, command string) {
+	for _, obj := range objects {
+		config, ok := obj.(*deployapi.DeploymentConfig)
+		if !ok || config.Template.ControllerTemplate.Template == nil {
+			continue
+		}
+		containers := config.Template.ControllerTemplate.Template.Spec.Containers
+		for i := range containers {
+			if containers[i].Lifecycle == nil {
+				containers[i].Lifecycle = &kapi.Lifecycle{}
+			}
+			containers[i].Lifecycle.PreStop = &kapi.Handler{
+				Exec: &kapi.ExecAction{Command: []string{"/bin/sh", "-c", command}},
+			}
+		}
+	}
+}
+
+// parseResourceLimits parses cpu and memory limits of the form resource=quantity
+func parseResourceLimits(pairs []string) (kapi.ResourceList, error) {
+	errs := []error{}
+	limits := kapi.ResourceList{}
+	for _, pair := range pairs {
+		p := strings.SplitN(pair, "=", 2)
+		if len(p) != 2 {
+			errs = append(errs, fmt.Errorf("resource limits must be of the form resource=quantity: %s", pair))
+			continue
+		}
+		name := kapi.ResourceName(p[0])
+		if name != kapi.ResourceCPU && name != kapi.ResourceMemory {
+			errs = append(errs, fmt.Errorf("resource limits may only be set for %s or %s: %s", kapi.ResourceCPU, kapi.ResourceMemory, pair))
+			continue
+		}
+		if _, exists := limits[name]; exists {
+			errs = append(errs, fmt.Errorf("the %s limit may only be specified once", name))
+			continue
+		}
+		quantity, err := resource.ParseQuantity(p[1])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s limit %q: %v", name, p[1], err))
+			continue
+		}
+		limits[name] = *quantity
+	}
+	if len(errs) > 0 {
+		return nil, errors.NewAggregate(errs)
+	}
+	return limits, nil
+}
+
+// setResourceLimits sets the resource limits of each container of the generated deployment configs
+func setResourceLimits(objects []runtime.Object, limits kapi.ResourceList) {
+	for _, obj := range objects {
+		config, ok := obj.(*deployapi.DeploymentConfig)
+		if !ok || config.Template.ControllerTemplate.Template == nil {
+			continue
+		}
+		containers := config.Template.ControllerTemplate.Template.Spec.Containers
+		for i := range containers {
+			containers[i].Resources.Limits = kapi.ResourceList{}
+			for name, quantity := range limits {
+				containers[i].Resources.Limits[name] = *quantity.Copy()
+			}
+		}
+	}
+}
+
+// imageTarget is the image repository a generated build pushes to
+type imageTarget struct {
+	namespace string
+	name      string
+	tag       string
+}
+
+// parseImageTarget parses an image repository reference of the form
+// [namespace/]name[:tag]
+func parseImageTarget(spec string) (*imageTarget, error) {
+	registry, namespace, name, tag, err := imageapi.SplitOpenShiftPullSpec(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --to-image %q: %v", spec, err)
+	}
+	if len(registry) > 0 {
+		return nil, fmt.Errorf("invalid --to-image %q: must be an image repository of the form [namespace/]name[:tag], not a registry location", spec)
+	}
+	if len(namespace) > 0 && !util.IsDNSLabel(namespace) {
+		return nil, fmt.Errorf("invalid --to-image %q: %q is not a valid namespace", spec, namespace)
+	}
+	if !util.IsDNSSubdomain(name) {
+		return nil, fmt.Errorf("invalid --to-image %q: %q is not a valid image repository name", spec, name)
+	}
+	return &imageTarget{namespace: namespace, name: name, tag: tag}, nil
+}
+
+// setImageTarget points the generated build output and deployment triggers at
+// the target image repository. When the target is in another namespace, the
+// image repository is expected to exist there and is not generated.
+func setImageTarget(objects []runtime.Object, target *imageTarget) []runtime.Object {
+	result := []runtime.Object{}
+	for _, obj := range objects {
+		switch t := obj.(type) {
+		case *imageapi.ImageRepository:
+			if len(target.namespace) > 0 && t.Name == target.name {
+				continue
+			}
+		case *buildapi.BuildConfig:
+			if t.Parameters.Output.To != nil {
+				t.Parameters.Output.To.Namespace = target.namespace
+				t.Parameters.Output.Tag = target.tag
+			}
+		case *deployapi.DeploymentConfig:
+			for _, trigger := range t.Triggers {
+				if trigger.ImageChangeParams != nil && trigger.ImageChangeParams.From.Name == target.name {
+					trigger.ImageChangeParams.From.Namespace = target.namespace
+				}
+			}
+		}
+		result = append(result, obj)
+	}
+	return result
+}
+
+// deploymentsFromConfigs replaces each generated deployment config with a
+// deployment of the same template. The deployment is not redeployed when the
+// build output changes.
+func deploymentsFromConfigs(objects []runtime.Object) []runtime.Object {
+	result := []runtime.Object{}
+	for _, obj := range objects {
+		config, ok := obj.(*deployapi.DeploymentConfig)
+		if !ok {
+			result = append(result, obj)
+			continue
+		}
+		result = append(result, &deployapi.Deployment{
+			ObjectMeta:         config.ObjectMeta,
+			Strategy:           config.Template.Strategy,
+			ControllerTemplate: config.Template.ControllerTemplate,
+		})
+	}
+	return result
+}
+
+// writeList encodes the list of generated objects to out in the json or yaml
+// format, merging them into the manifest at mergeInto if it is set
+func writeList(list *kapi.List, mergeInto, templateName, format string, out io.Writer) error {
+	if len(mergeInto) > 0 {
+		merged, warnings, err := mergeIntoManifest(mergeInto, list.Items)
+		if err != nil {
+			return err
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+		list = &kapi.List{Items: merged}
+	}
+	var obj runtime.Object = list
+	if len(templateName) > 0 {
+		obj = newTemplate(templateName, list.Items)
+	}
+	output, err := latest.Codec.Encode(obj)
 	if err != nil {
 		return err
 	}
+	if format == "yaml" {
+		if output, err = yaml.JSONToYAML(output); err != nil {
+			return err
+		}
+	}
 	_, err = out.Write(output)
 	return err
 }
 
+// validateHTTPHeaders ensures each header is of the form "Name: Value"
+func validateHTTPHeaders(headers []string) error {
+	errs := []error{}
+	for _, header := range headers {
+		// a line break would allow additional headers to be injected into the request
+		if strings.ContainsAny(header, "\r\n") {
+			errs = append(errs, fmt.Errorf("HTTP headers may not contain line breaks: %q", header))
+			continue
+		}
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 || strings.ContainsAny(parts[0], " \t") {
+			errs = append(errs, fmt.Errorf("HTTP headers must be of the form \"Name: Value\": %s", header))
+		}
+	}
+	return errors.NewAggregate(errs)
+}
+
 func exitWithError(err error) {
 	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 	os.Exit(1)
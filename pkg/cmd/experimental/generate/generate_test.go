@@ -0,0 +1,808 @@
+package generate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	cmdutil "github.com/openshift/origin/pkg/cmd/util"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+func TestValidateHTTPHeaders(t *testing.T) {
+	tests := map[string]struct {
+		headers []string
+		valid   bool
+	}{
+		"none":            {headers: nil, valid: true},
+		"single":          {headers: []string{"X-Auth-Token: abc"}, valid: true},
+		"multiple":        {headers: []string{"X-Auth-Token: abc", "Authorization: Bearer abc"}, valid: true},
+		"empty value":     {headers: []string{"X-Empty:"}, valid: true},
+		"value has colon": {headers: []string{"X-Url: http://example.com"}, valid: true},
+		"no colon":        {headers: []string{"X-Auth-Token abc"}, valid: false},
+		"empty name":      {headers: []string{": abc"}, valid: false},
+		"space in name":   {headers: []string{"X Auth: abc"}, valid: false},
+		"tab in name":     {headers: []string{"X\tAuth: abc"}, valid: false},
+		"carriage return": {headers: []string{"X-Auth-Token: abc\rX-Other: def"}, valid: false},
+		"line feed":       {headers: []string{"X-Auth-Token: abc\nX-Other: def"}, valid: false},
+		"one invalid":     {headers: []string{"X-Auth-Token: abc", "invalid"}, valid: false},
+	}
+	for name, test := range tests {
+		err := validateHTTPHeaders(test.headers)
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDecodeDocuments(t *testing.T) {
+	tests := map[string]struct {
+		data      string
+		names     []string
+		expectErr bool
+	}{
+		"empty": {
+			data:  "",
+			names: []string{},
+		},
+		"single document": {
+			data:  "kind: Service\napiVersion: v1beta1\nid: frontend\n",
+			names: []string{"frontend"},
+		},
+		"multiple documents": {
+			data:  "kind: Service\napiVersion: v1beta1\nid: frontend\n---\nkind: Service\napiVersion: v1beta1\nid: database\n",
+			names: []string{"frontend", "database"},
+		},
+		"empty documents and trailing whitespace on separators": {
+			data:  "---\nkind: Service\napiVersion: v1beta1\nid: frontend\n---  \n\n---\n",
+			names: []string{"frontend"},
+		},
+		"windows line endings": {
+			data:  "kind: Service\r\napiVersion: v1beta1\r\nid: frontend\r\n---\r\nkind: Service\r\napiVersion: v1beta1\r\nid: database\r\n",
+			names: []string{"frontend", "database"},
+		},
+		"long lines": {
+			data:  "kind: Service\napiVersion: v1beta1\nid: frontend\nannotations:\n  cert: " + strings.Repeat("a", 256*1024) + "\n---\n" + `{"kind":"Service","apiVersion":"v1beta1","id":"database","annotations":{"key":"` + strings.Repeat("b", 256*1024) + `"}}` + "\n",
+			names: []string{"frontend", "database"},
+		},
+		"invalid yaml": {
+			data:      "kind: Service\n\tid: [frontend\n",
+			expectErr: true,
+		},
+		"unknown kind": {
+			data:      "kind: Widget\napiVersion: v1beta1\nid: frontend\n",
+			expectErr: true,
+		},
+	}
+	for name, test := range tests {
+		objects, err := decodeDocuments([]byte(test.data))
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		names := []string{}
+		for _, obj := range objects {
+			svc, ok := obj.(*kapi.Service)
+			if !ok {
+				t.Errorf("%s: unexpected object: %#v", name, obj)
+				continue
+			}
+			names = append(names, svc.Name)
+		}
+		if !reflect.DeepEqual(names, test.names) {
+			t.Errorf("%s: expected %v, got %v", name, test.names, names)
+		}
+	}
+}
+
+func TestMergeIntoManifest(t *testing.T) {
+	manifest := "kind: Service\napiVersion: v1beta1\nid: frontend\nport: 80\n---\nkind: Service\napiVersion: v1beta1\nid: database\nport: 5432\n"
+	tests := map[string]struct {
+		manifest  string
+		generated []runtime.Object
+		expected  []string
+		warnings  int
+		expectErr bool
+	}{
+		"appends new objects": {
+			manifest:  manifest,
+			generated: []runtime.Object{&kapi.Service{ObjectMeta: kapi.ObjectMeta{Name: "cache"}}},
+			expected:  []string{"Service/frontend:80", "Service/database:5432", "Service/cache:0"},
+		},
+		"replaces objects of the same kind and name in place": {
+			manifest:  manifest,
+			generated: []runtime.Object{&kapi.Service{ObjectMeta: kapi.ObjectMeta{Name: "frontend"}, Spec: kapi.ServiceSpec{Port: 8080}}},
+			expected:  []string{"Service/frontend:8080", "Service/database:5432"},
+			warnings:  1,
+		},
+		"objects of another kind are not replaced": {
+			manifest:  manifest,
+			generated: []runtime.Object{&deployapi.DeploymentConfig{ObjectMeta: kapi.ObjectMeta{Name: "frontend"}}},
+			expected:  []string{"Service/frontend:80", "Service/database:5432", "DeploymentConfig/frontend"},
+		},
+		"duplicate objects in the manifest": {
+			manifest:  manifest + "---\nkind: Service\napiVersion: v1beta1\nid: frontend\nport: 81\n",
+			expectErr: true,
+		},
+		"invalid manifest": {
+			manifest:  "kind: Widget\n",
+			expectErr: true,
+		},
+	}
+	for name, test := range tests {
+		f, err := ioutil.TempFile("", "manifest")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer os.Remove(f.Name())
+		if _, err := f.WriteString(test.manifest); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		f.Close()
+
+		merged, warnings, err := mergeIntoManifest(f.Name(), test.generated)
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if len(warnings) != test.warnings {
+			t.Errorf("%s: expected %d warnings, got %v", name, test.warnings, warnings)
+		}
+		keys := []string{}
+		for _, obj := range merged {
+			key, err := objectKey(obj)
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", name, err)
+			}
+			if svc, ok := obj.(*kapi.Service); ok {
+				key = fmt.Sprintf("%s:%d", key, svc.Spec.Port)
+			}
+			keys = append(keys, key)
+		}
+		if !reflect.DeepEqual(keys, test.expected) {
+			t.Errorf("%s: expected %v, got %v", name, test.expected, keys)
+		}
+	}
+}
+
+func TestMergeIntoMissingManifest(t *testing.T) {
+	if _, _, err := mergeIntoManifest("/does/not/exist.yaml", nil); err == nil {
+		t.Errorf("expected an error for a missing manifest")
+	}
+}
+
+func TestParseKeyValues(t *testing.T) {
+	tests := map[string]struct {
+		pairs     []string
+		expected  map[string]string
+		expectErr bool
+	}{
+		"none": {
+			pairs:    []string{},
+			expected: map[string]string{},
+		},
+		"valid": {
+			pairs:    []string{"app=frontend", "example.com/owner=ops"},
+			expected: map[string]string{"app": "frontend", "example.com/owner": "ops"},
+		},
+		"empty value": {
+			pairs:    []string{"app="},
+			expected: map[string]string{"app": ""},
+		},
+		"value contains equals": {
+			pairs:    []string{"query=a=b"},
+			expected: map[string]string{"query": "a=b"},
+		},
+		"missing equals":  {pairs: []string{"app"}, expectErr: true},
+		"empty key":       {pairs: []string{"=frontend"}, expectErr: true},
+		"invalid key":     {pairs: []string{"my app=frontend"}, expectErr: true},
+		"empty list item": {pairs: []string{"app=frontend", ""}, expectErr: true},
+		"duplicate key":   {pairs: []string{"app=frontend", "app=backend"}, expectErr: true},
+		"one of many bad": {pairs: []string{"app=frontend", "tier"}, expectErr: true},
+		"repeated pair":   {pairs: []string{"app=frontend", "app=frontend"}, expectErr: true},
+	}
+	for name, test := range tests {
+		for kind, parse := range map[string]func([]string) (map[string]string, error){"annotations": parseAnnotations, "labels": parseLabels} {
+			values, err := parse(test.pairs)
+			if test.expectErr {
+				if err == nil {
+					t.Errorf("%s %s: expected an error", kind, name)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%s %s: unexpected error: %v", kind, name, err)
+				continue
+			}
+			if !reflect.DeepEqual(values, test.expected) {
+				t.Errorf("%s %s: expected %v, got %v", kind, name, test.expected, values)
+			}
+		}
+	}
+}
+
+func TestScopeValues(t *testing.T) {
+	tests := map[string]struct {
+		values []string
+		global []string
+		scoped map[string][]string
+	}{
+		"none": {
+			values: []string{},
+			global: []string{},
+			scoped: map[string][]string{},
+		},
+		"global values": {
+			values: []string{"8080", "DEBUG=1"},
+			global: []string{"8080", "DEBUG=1"},
+			scoped: map[string][]string{},
+		},
+		"scoped values": {
+			values: []string{"frontend=8080", "backend=DEBUG=1", "backend=9090"},
+			global: []string{},
+			scoped: map[string][]string{"frontend": {"8080"}, "backend": {"DEBUG=1", "9090"}},
+		},
+		"unknown names are global": {
+			values: []string{"cache=8080", "frontend=8081"},
+			global: []string{"cache=8080"},
+			scoped: map[string][]string{"frontend": {"8081"}},
+		},
+		"empty scoped value": {
+			values: []string{"frontend="},
+			global: []string{},
+			scoped: map[string][]string{"frontend": {""}},
+		},
+	}
+	for name, test := range tests {
+		global, scoped, err := scopeValues(test.values, []string{"frontend", "backend"}, false)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(global, test.global) {
+			t.Errorf("%s: expected global values %v, got %v", name, test.global, global)
+		}
+		if !reflect.DeepEqual(scoped, test.scoped) {
+			t.Errorf("%s: expected scoped values %v, got %v", name, test.scoped, scoped)
+		}
+	}
+}
+
+func TestScopeEnvironmentValues(t *testing.T) {
+	names := []string{"app", "worker"}
+	global, scoped, err := scopeValues([]string{"DEBUG=1", "app=NAME=web", "worker=NAME=jobs"}, names, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(global, []string{"DEBUG=1"}) || !reflect.DeepEqual(scoped, map[string][]string{"app": {"NAME=web"}, "worker": {"NAME=jobs"}}) {
+		t.Errorf("unexpected values: %v %v", global, scoped)
+	}
+
+	// a variable with the same name as a source is ambiguous
+	for _, value := range []string{"app=1", "app="} {
+		if _, _, err := scopeValues([]string{value}, names, true); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+			t.Errorf("%s: expected an ambiguous value error, got %v", value, err)
+		}
+	}
+	// ports have no key, so a source name prefix is always a scope
+	if _, scoped, err := scopeValues([]string{"app=8080"}, names, false); err != nil || !reflect.DeepEqual(scoped, map[string][]string{"app": {"8080"}}) {
+		t.Errorf("unexpected scoped ports: %v %v", scoped, err)
+	}
+}
+
+func TestSourceValues(t *testing.T) {
+	tests := map[string]struct {
+		ports     []string
+		env       []string
+		expPorts  []string
+		expEnv    map[string]string
+		expectErr bool
+	}{
+		"none": {
+			expPorts: []string{},
+		},
+		"ports are split": {
+			ports:    []string{"8080,8443", "9090"},
+			expPorts: []string{"8080", "8443", "9090"},
+		},
+		"later environment values win": {
+			env:      []string{"DEBUG=0", "NAME=app", "DEBUG=1"},
+			expPorts: []string{},
+			expEnv:   map[string]string{"DEBUG": "1", "NAME": "app"},
+		},
+		"malformed environment": {
+			env:       []string{"DEBUG"},
+			expectErr: true,
+		},
+		"empty environment name": {
+			env:       []string{"=1"},
+			expectErr: true,
+		},
+	}
+	for name, test := range tests {
+		ports, env, err := sourceValues(test.ports, test.env)
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(ports, test.expPorts) {
+			t.Errorf("%s: expected ports %v, got %v", name, test.expPorts, ports)
+		}
+		if len(env) != len(test.expEnv) {
+			t.Errorf("%s: expected environment %v, got %v", name, test.expEnv, env)
+		}
+		for k, v := range test.expEnv {
+			if env[k] != v {
+				t.Errorf("%s: expected %s=%s, got %v", name, k, v, env)
+			}
+		}
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := map[string]struct {
+		values   []string
+		expected []string
+	}{
+		"none":             {values: nil, expected: []string{}},
+		"single":           {values: []string{"8080"}, expected: []string{"8080"}},
+		"comma separated":  {values: []string{"8080,8443"}, expected: []string{"8080", "8443"}},
+		"whitespace":       {values: []string{" 8080 , 8443 "}, expected: []string{"8080", "8443"}},
+		"empty list items": {values: []string{"8080,,8443,", "", ","}, expected: []string{"8080", "8443"}},
+	}
+	for name, test := range tests {
+		if result := splitList(test.values); !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%s: expected %v, got %v", name, test.expected, result)
+		}
+	}
+}
+
+func TestRecordGenerated(t *testing.T) {
+	generatedFrom := map[string]string{}
+	frontend := []runtime.Object{
+		&kapi.Service{ObjectMeta: kapi.ObjectMeta{Name: "frontend"}},
+		&deployapi.DeploymentConfig{ObjectMeta: kapi.ObjectMeta{Name: "frontend"}},
+	}
+	if err := recordGenerated(generatedFrom, frontend, "./frontend"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	backend := []runtime.Object{&kapi.Service{ObjectMeta: kapi.ObjectMeta{Name: "backend"}}}
+	if err := recordGenerated(generatedFrom, backend, "./backend"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if generatedFrom["Service/frontend"] != "./frontend" || generatedFrom["Service/backend"] != "./backend" {
+		t.Errorf("unexpected sources: %v", generatedFrom)
+	}
+
+	duplicate := []runtime.Object{&kapi.Service{ObjectMeta: kapi.ObjectMeta{Name: "frontend"}}}
+	err := recordGenerated(generatedFrom, duplicate, "./other")
+	if err == nil {
+		t.Fatalf("expected an error for a duplicate object")
+	}
+	for _, expected := range []string{"./frontend", "./other", "Service/frontend"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q in error: %v", expected, err)
+		}
+	}
+}
+
+func TestParseImageTarget(t *testing.T) {
+	tests := map[string]struct {
+		spec      string
+		expected  imageTarget
+		expectErr bool
+	}{
+		"name":                     {spec: "app", expected: imageTarget{name: "app"}},
+		"name and tag":             {spec: "app:v1", expected: imageTarget{name: "app", tag: "v1"}},
+		"namespace, name and tag":  {spec: "shared/app:v1", expected: imageTarget{namespace: "shared", name: "app", tag: "v1"}},
+		"empty":                    {spec: "", expectErr: true},
+		"registry":                 {spec: "registry.example.com:5000/shared/app", expectErr: true},
+		"too many segments":        {spec: "a/b/c/d", expectErr: true},
+		"invalid namespace":        {spec: "Shared_Images/app", expectErr: true},
+		"invalid repository name":  {spec: "shared/App!", expectErr: true},
+		"invalid name without tag": {spec: "-app", expectErr: true},
+	}
+	for name, test := range tests {
+		target, err := parseImageTarget(test.spec)
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %#v", name, target)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if *target != test.expected {
+			t.Errorf("%s: expected %#v, got %#v", name, test.expected, *target)
+		}
+	}
+}
+
+type fakeDocker struct {
+	err error
+}
+
+func (d fakeDocker) Ping() error {
+	return d.err
+}
+
+type fakeServer struct {
+	*osclient.Fake
+	err error
+}
+
+func (c *fakeServer) ImageRepositories(namespace string) osclient.ImageRepositoryInterface {
+	return &fakeServerImageRepositories{osclient.FakeImageRepositories{Fake: c.Fake, Namespace: namespace}, c.err}
+}
+
+type fakeServerImageRepositories struct {
+	osclient.FakeImageRepositories
+	err error
+}
+
+func (c *fakeServerImageRepositories) List(label, field labels.Selector) (*imageapi.ImageRepositoryList, error) {
+	c.FakeImageRepositories.List(label, field)
+	return &imageapi.ImageRepositoryList{}, c.err
+}
+
+func TestCheckImageSources(t *testing.T) {
+	unreachable := &url.Error{Op: "Get", URL: "https://localhost:8443/osapi/v1beta1/imageRepositories", Err: fmt.Errorf("connection refused")}
+	tests := map[string]struct {
+		namespace            string
+		serverErr, osErr     error
+		pingErr, dockerErr   error
+		allowMissing         bool
+		expectServer         bool
+		expectDocker         bool
+		expectErr            bool
+		expectServerRequests int
+	}{
+		"both reachable": {
+			namespace:            "test",
+			expectServer:         true,
+			expectDocker:         true,
+			expectServerRequests: 1,
+		},
+		"server unreachable": {
+			namespace:            "test",
+			serverErr:            unreachable,
+			expectDocker:         true,
+			expectServerRequests: 1,
+		},
+		"server forbids the request": {
+			namespace:            "test",
+			serverErr:            kerrors.NewForbidden("imageRepositories", "", fmt.Errorf("not allowed")),
+			pingErr:              unreachable,
+			expectServer:         true,
+			expectServerRequests: 1,
+		},
+		"server returns not found": {
+			namespace:            "test",
+			serverErr:            kerrors.NewNotFound("namespace", "test"),
+			expectServer:         true,
+			expectDocker:         true,
+			expectServerRequests: 1,
+		},
+		"docker unreachable": {
+			namespace:            "test",
+			pingErr:              unreachable,
+			expectServer:         true,
+			expectServerRequests: 1,
+		},
+		"neither reachable": {
+			namespace:            "test",
+			serverErr:            unreachable,
+			pingErr:              unreachable,
+			expectErr:            true,
+			expectServerRequests: 1,
+		},
+		"neither reachable with allow missing images": {
+			namespace:            "test",
+			serverErr:            unreachable,
+			pingErr:              unreachable,
+			allowMissing:         true,
+			expectServerRequests: 1,
+		},
+		"no namespace is not checked": {
+			serverErr:    unreachable,
+			expectServer: true,
+			expectDocker: true,
+		},
+		"clients could not be created": {
+			namespace: "test",
+			osErr:     fmt.Errorf("no configuration"),
+			dockerErr: fmt.Errorf("no DOCKER_HOST"),
+			expectErr: true,
+		},
+	}
+	for name, test := range tests {
+		server := &fakeServer{&osclient.Fake{}, test.serverErr}
+		serverOK, dockerOK, err := checkImageSources(test.namespace, server, test.osErr, fakeDocker{test.pingErr}, test.dockerErr, test.allowMissing)
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if serverOK != test.expectServer || dockerOK != test.expectDocker {
+			t.Errorf("%s: expected server %t and docker %t, got %t and %t", name, test.expectServer, test.expectDocker, serverOK, dockerOK)
+		}
+		if len(server.Actions) != test.expectServerRequests {
+			t.Errorf("%s: expected %d requests to the server, got %v", name, test.expectServerRequests, server.Actions)
+		}
+	}
+}
+
+func TestReadEnvFile(t *testing.T) {
+	tests := map[string]struct {
+		contents  string
+		expected  []string
+		expectErr bool
+	}{
+		"empty": {
+			contents: "",
+			expected: []string{},
+		},
+		"values, comments and blank lines": {
+			contents: "# settings\nDEBUG=1\n\n  NAME=my app  \nQUERY=a=b\nEMPTY=\n",
+			expected: []string{"DEBUG=1", "NAME=my app", "QUERY=a=b", "EMPTY="},
+		},
+		"repeated names are kept in order": {
+			contents: "DEBUG=0\nDEBUG=1\n",
+			expected: []string{"DEBUG=0", "DEBUG=1"},
+		},
+		"missing equals": {contents: "DEBUG=1\nNAME\n", expectErr: true},
+		"empty name":     {contents: "=1\n", expectErr: true},
+		"shell syntax":   {contents: "DEBUG=1\n  # comment\nexport NAME\n", expectErr: true},
+	}
+	for name, test := range tests {
+		f, err := ioutil.TempFile("", "env")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer os.Remove(f.Name())
+		if _, err := f.WriteString(test.contents); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		f.Close()
+
+		env, err := readEnvFile(f.Name())
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", name)
+			} else if !strings.Contains(err.Error(), f.Name()+":") {
+				t.Errorf("%s: expected the file and line in the error: %v", name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(env, test.expected) {
+			t.Errorf("%s: expected %v, got %v", name, test.expected, env)
+		}
+	}
+}
+
+func TestReadEnvFileLineNumbers(t *testing.T) {
+	f, err := ioutil.TempFile("", "env")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("# settings\n\nDEBUG\n")
+	f.Close()
+
+	_, err = readEnvFile(f.Name())
+	if err == nil || !strings.Contains(err.Error(), f.Name()+":3:") {
+		t.Errorf("expected an error on line 3, got %v", err)
+	}
+}
+
+func TestReadMissingEnvFile(t *testing.T) {
+	if _, err := readEnvFile("/does/not/exist.env"); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
+
+func TestParseResourceLimits(t *testing.T) {
+	tests := map[string]struct {
+		pairs     []string
+		expected  map[kapi.ResourceName]string
+		expectErr bool
+	}{
+		"cpu and memory": {
+			pairs:    []string{"cpu=500m", "memory=256Mi"},
+			expected: map[kapi.ResourceName]string{kapi.ResourceCPU: "500m", kapi.ResourceMemory: "256Mi"},
+		},
+		"memory only": {
+			pairs:    []string{"memory=1Gi"},
+			expected: map[kapi.ResourceName]string{kapi.ResourceMemory: "1Gi"},
+		},
+		"missing equals":    {pairs: []string{"cpu"}, expectErr: true},
+		"unknown resource":  {pairs: []string{"disk=1Gi"}, expectErr: true},
+		"invalid quantity":  {pairs: []string{"cpu=lots"}, expectErr: true},
+		"empty quantity":    {pairs: []string{"memory="}, expectErr: true},
+		"empty list item":   {pairs: []string{"cpu=1", ""}, expectErr: true},
+		"repeated resource": {pairs: []string{"cpu=1", "cpu=2"}, expectErr: true},
+	}
+	for name, test := range tests {
+		limits, err := parseResourceLimits(test.pairs)
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %v", name, limits)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if len(limits) != len(test.expected) {
+			t.Errorf("%s: expected %v, got %v", name, test.expected, limits)
+		}
+		for resource, value := range test.expected {
+			quantity, ok := limits[resource]
+			if !ok {
+				t.Errorf("%s: expected a %s limit", name, resource)
+				continue
+			}
+			if quantity.String() != value {
+				t.Errorf("%s: expected %s=%s, got %s", name, resource, value, quantity.String())
+			}
+		}
+	}
+}
+
+func TestSetResourceLimits(t *testing.T) {
+	limits, err := parseResourceLimits([]string{"cpu=500m"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config := &deployapi.DeploymentConfig{}
+	config.Template.ControllerTemplate.Template = &kapi.PodTemplateSpec{
+		Spec: kapi.PodSpec{Containers: []kapi.Container{{Name: "a"}, {Name: "b"}}},
+	}
+	setResourceLimits([]runtime.Object{config, &kapi.Service{}}, limits)
+	containers := config.Template.ControllerTemplate.Template.Spec.Containers
+	for _, container := range containers {
+		if cpu := container.Resources.Limits[kapi.ResourceCPU]; cpu.String() != "500m" {
+			t.Errorf("expected a cpu limit on container %s, got %v", container.Name, container.Resources.Limits)
+		}
+	}
+	// each container has its own copy of the quantity
+	if containers[0].Resources.Limits[kapi.ResourceCPU].Amount == containers[1].Resources.Limits[kapi.ResourceCPU].Amount {
+		t.Errorf("expected the limits of each container to be independent")
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	if err := withTimeout(0, func(stop <-chan struct{}) error {
+		if stop != nil {
+			t.Errorf("expected no stop channel without a timeout")
+		}
+		return nil
+	}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	expected := fmt.Errorf("failed")
+	if err := withTimeout(time.Minute, func(stop <-chan struct{}) error {
+		return expected
+	}); err != expected {
+		t.Errorf("expected the error of fn, got %v", err)
+	}
+
+	stopped := false
+	err := withTimeout(10*time.Millisecond, func(stop <-chan struct{}) error {
+		select {
+		case <-stop:
+			stopped = true
+			return fmt.Errorf("stopped")
+		case <-time.After(time.Minute):
+			return nil
+		}
+	})
+	if err == nil || !strings.Contains(err.Error(), "did not complete within") {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+	// withTimeout waits for fn to stop before returning
+	if !stopped {
+		t.Errorf("expected fn to be stopped when the timeout expired")
+	}
+}
+
+func TestSourceTemplateConflicts(t *testing.T) {
+	defaults := params{strategy: autoStrategy, serviceType: clusterIPServiceType, deployConfig: true}
+	if conflicts := sourceTemplateConflicts(defaults); len(conflicts) != 0 {
+		t.Errorf("unexpected conflicts for the default flags: %v", conflicts)
+	}
+
+	input := defaults
+	input.expose = true
+	input.limits = kapi.ResourceList{kapi.ResourceCPU: resource.MustParse("1")}
+	input.env = cmdutil.Environment{"DEBUG": "1"}
+	input.deployConfig = false
+	expected := []string{"--environment or --env-file", "--expose", "--limits", "--deployment-config=false"}
+	if conflicts := sourceTemplateConflicts(input); !reflect.DeepEqual(conflicts, expected) {
+		t.Errorf("expected %v, got %v", expected, conflicts)
+	}
+}
+
+func TestGenerateObjectsFromSourceTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "source")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(filepath.Join(dir, sourceTemplateDir), 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	template := `{"kind":"Template","apiVersion":"v1beta1","metadata":{"name":"app"},"items":[{"kind":"Service","apiVersion":"v1beta1","id":"frontend","port":8080}]}`
+	if err := ioutil.WriteFile(filepath.Join(dir, sourceTemplateDir, "app.json"), []byte(template), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	input := params{sourceDir: dir, strategy: autoStrategy, serviceType: clusterIPServiceType, deployConfig: true}
+	objects, err := generateObjects(input, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(objects) != 1 {
+		t.Errorf("expected the template objects, got %#v", objects)
+	}
+
+	input.expose = true
+	input.annotations = map[string]string{"owner": "ops"}
+	_, err = generateObjects(input, nil)
+	if err == nil {
+		t.Fatalf("expected an error for flags that do not apply to a source template")
+	}
+	for _, flag := range []string{"--expose", "--annotations"} {
+		if !strings.Contains(err.Error(), flag) {
+			t.Errorf("expected %s in the error: %v", flag, err)
+		}
+	}
+}
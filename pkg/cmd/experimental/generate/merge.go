@@ -0,0 +1,107 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/ghodss/yaml"
+
+	"github.com/openshift/origin/pkg/api/latest"
+)
+
+// mergeIntoManifest reads the objects from a multi-document YAML manifest and
+// merges the generated objects into them. A generated object replaces a manifest
+// object of the same kind and name, and a warning is returned for each replaced
+// object. Generated objects that are not in the manifest are appended.
+func mergeIntoManifest(path string, generated []runtime.Object) ([]runtime.Object, []string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	base, err := decodeDocuments(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read %s: %v", path, err)
+	}
+
+	index := map[string]int{}
+	for i, obj := range base {
+		key, err := objectKey(obj)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to read %s: %v", path, err)
+		}
+		if _, exists := index[key]; exists {
+			return nil, nil, fmt.Errorf("unable to read %s: %s is defined more than once", path, key)
+		}
+		index[key] = i
+	}
+
+	warnings := []string{}
+	merged := base
+	for _, obj := range generated {
+		key, err := objectKey(obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		if i, exists := index[key]; exists {
+			warnings = append(warnings, fmt.Sprintf("%s in %s was replaced by the generated object", key, path))
+			merged[i] = obj
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, obj)
+	}
+	return merged, warnings, nil
+}
+
+// decodeDocuments decodes each non-empty document of a YAML stream
+// separated by "---" lines. Lines are split directly rather than scanned so
+// that long lines, such as inline JSON or certificates, are not limited in length.
+func decodeDocuments(data []byte) ([]runtime.Object, error) {
+	documents := [][]byte{}
+	current := &bytes.Buffer{}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if string(bytes.TrimRight(line, " \t")) == "---" {
+			documents = append(documents, current.Bytes())
+			current = &bytes.Buffer{}
+			continue
+		}
+		current.Write(line)
+		current.WriteString("\n")
+	}
+	documents = append(documents, current.Bytes())
+
+	objects := []runtime.Object{}
+	for i, document := range documents {
+		if len(bytes.TrimSpace(document)) == 0 {
+			continue
+		}
+		json, err := yaml.YAMLToJSON(document)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %v", i+1, err)
+		}
+		obj, err := latest.Codec.Decode(json)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %v", i+1, err)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// objectKey identifies an object by its kind and name
+func objectKey(obj runtime.Object) (string, error) {
+	_, kind, err := kapi.Scheme.ObjectVersionAndKind(obj)
+	if err != nil {
+		return "", err
+	}
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s", kind, objMeta.Name()), nil
+}
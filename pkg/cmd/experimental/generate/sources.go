@@ -0,0 +1,177 @@
+package generate
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/errors"
+
+	cmdutil "github.com/openshift/origin/pkg/cmd/util"
+	genapp "github.com/openshift/origin/pkg/generate/app"
+)
+
+// generateApps generates the objects for each of the sources and writes them
+// as a single list. When more than one source is provided, --port and
+// --environment values of the form name=value apply only to the source with
+// that name, and the name of each source is prefixed with --name if it is set.
+func generateApps(input params, sources []string, imageResolver genapp.Resolver, out io.Writer) error {
+	if len(sources) <= 1 {
+		ports, env, err := sourceValues(input.portArgs, input.envArgs)
+		if err != nil {
+			return err
+		}
+		input.ports, input.env = ports, env
+		return generateApp(input, imageResolver, out)
+	}
+
+	if input.toImage != nil {
+		return fmt.Errorf("--to-image may not be used with more than one source")
+	}
+
+	inputs := []params{}
+	names := []string{}
+	for _, source := range sources {
+		sourceInput := input
+		sourceInput.sourceDir, sourceInput.sourceURL = "", ""
+		if genapp.IsRemoteRepository(source) {
+			sourceInput.sourceURL = source
+		} else {
+			sourceInput.sourceDir = source
+		}
+		srcRef, err := generateSourceRef(sourceInput.sourceURL, sourceInput.sourceDir, sourceInput.sourceRef, "", sourceInput.contextDir)
+		if err != nil {
+			return err
+		}
+		name, ok := srcRef.SuggestName()
+		if !ok {
+			return fmt.Errorf("unable to suggest a name for the source %s", source)
+		}
+		inputs = append(inputs, sourceInput)
+		names = append(names, name)
+	}
+
+	globalPorts, scopedPorts, err := scopeValues(splitList(input.portArgs), names, false)
+	if err != nil {
+		return err
+	}
+	globalEnv, scopedEnv, err := scopeValues(input.envArgs, names, true)
+	if err != nil {
+		return err
+	}
+
+	objects := []runtime.Object{}
+	generatedFrom := map[string]string{}
+	for i, sourceInput := range inputs {
+		name := names[i]
+		ports := globalPorts
+		if len(scopedPorts[name]) > 0 {
+			ports = scopedPorts[name]
+		}
+		ports, env, err := sourceValues(ports, append(append([]string{}, globalEnv...), scopedEnv[name]...))
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		sourceInput.ports, sourceInput.env = ports, env
+		sourceInput.name = name
+		if len(input.name) > 0 {
+			sourceInput.name = fmt.Sprintf("%s-%s", input.name, name)
+		}
+
+		if input.dryRun {
+			fmt.Fprintf(out, "%s:\n", sources[i])
+			if err := printDetection(sourceInput, imageResolver, out); err != nil {
+				return fmt.Errorf("%s: %v", sources[i], err)
+			}
+			continue
+		}
+
+		generated, err := generateObjects(sourceInput, imageResolver)
+		if err != nil {
+			return fmt.Errorf("%s: %v", sources[i], err)
+		}
+		if err := recordGenerated(generatedFrom, generated, sources[i]); err != nil {
+			return err
+		}
+		objects = append(objects, generated...)
+	}
+	if input.dryRun {
+		return nil
+	}
+	if err := addLabels(objects, input.labels); err != nil {
+		return err
+	}
+	return writeList(&kapi.List{Items: objects}, input.mergeInto, input.templateName, input.output, out)
+}
+
+// recordGenerated records the source that generated each object in generatedFrom,
+// returning an error if another source already generated an object of the same
+// kind and name
+func recordGenerated(generatedFrom map[string]string, objects []runtime.Object, source string) error {
+	for _, obj := range objects {
+		key, err := objectKey(obj)
+		if err != nil {
+			return err
+		}
+		if existing, exists := generatedFrom[key]; exists {
+			return fmt.Errorf("the sources %s and %s both generate %s, use --name to distinguish them", existing, source, key)
+		}
+		generatedFrom[key] = source
+	}
+	return nil
+}
+
+// scopeValues splits values of the form name=value, where name is one of names,
+// from values that apply to every source. If pairs is set, values are of the form
+// key=value, and a value such as app=1 where app is a source name is rejected
+// since it could be meant for every source.
+func scopeValues(values []string, names []string, pairs bool) ([]string, map[string][]string, error) {
+	known := map[string]bool{}
+	for _, name := range names {
+		known[name] = true
+	}
+	global := []string{}
+	scoped := map[string][]string{}
+	for _, value := range values {
+		p := strings.SplitN(value, "=", 2)
+		if len(p) == 2 && known[p[0]] {
+			if pairs && !strings.Contains(p[1], "=") {
+				return nil, nil, fmt.Errorf("%q is ambiguous because %q is the name of a source, use %s=NAME=VALUE to set a variable for that source", value, p[0], p[0])
+			}
+			scoped[p[0]] = append(scoped[p[0]], p[1])
+			continue
+		}
+		global = append(global, value)
+	}
+	return global, scoped, nil
+}
+
+// sourceValues returns the ports and environment that apply to a single source.
+// Ports may be comma-separated lists, and later environment values override
+// earlier ones.
+func sourceValues(ports []string, envVars []string) ([]string, cmdutil.Environment, error) {
+	ports = splitList(ports)
+	if len(envVars) == 0 {
+		return ports, nil, nil
+	}
+	env, _, errs := cmdutil.ParseEnvironmentArguments(envVars)
+	if len(errs) > 0 {
+		return nil, nil, errors.NewAggregate(errs)
+	}
+	return ports, env, nil
+}
+
+// splitList splits comma-separated values, dropping empty values
+func splitList(values []string) []string {
+	result := []string{}
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); len(item) > 0 {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package generate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/errors"
+	"github.com/ghodss/yaml"
+
+	"github.com/openshift/origin/pkg/api/latest"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	"github.com/openshift/origin/pkg/template"
+	templateapi "github.com/openshift/origin/pkg/template/api"
+	"github.com/openshift/origin/pkg/template/generator"
+)
+
+// sourceTemplateDir is the directory, relative to the source root, that may
+// contain a template describing the application
+const sourceTemplateDir = ".openshift/templates"
+
+// findSourceTemplate looks for a single template in the templates directory of
+// a local source repository. It returns false if no template is present.
+func findSourceTemplate(dir string) (*templateapi.Template, bool, error) {
+	files := []string{}
+	for _, pattern := range []string{"*.json", "*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, sourceTemplateDir, pattern))
+		if err != nil {
+			return nil, false, err
+		}
+		files = append(files, matches...)
+	}
+	switch len(files) {
+	case 0:
+		return nil, false, nil
+	case 1:
+	default:
+		sort.Strings(files)
+		return nil, false, fmt.Errorf("multiple templates found in %s: %s", sourceTemplateDir, strings.Join(files, ", "))
+	}
+
+	data, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		return nil, false, err
+	}
+	if data, err = yaml.YAMLToJSON(data); err != nil {
+		return nil, false, fmt.Errorf("unable to read template %s: %v", files[0], err)
+	}
+	obj, err := latest.Codec.Decode(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to read template %s: %v", files[0], err)
+	}
+	t, ok := obj.(*templateapi.Template)
+	if !ok {
+		return nil, false, fmt.Errorf("%s does not contain a template", files[0])
+	}
+	return t, true, nil
+}
+
+// processSourceTemplate sets the provided NAME=VALUE parameters on the template and
+// processes it into a list of objects. Parameters without a value or a generator
+// must be provided.
+func processSourceTemplate(t *templateapi.Template, values []string) (*kapi.List, error) {
+	errs := []error{}
+	for _, keypair := range values {
+		p := strings.SplitN(keypair, "=", 2)
+		if len(p) != 2 {
+			errs = append(errs, fmt.Errorf("template parameters must be of the form NAME=VALUE: %s", keypair))
+			continue
+		}
+		param := template.GetParameterByName(t, p[0])
+		if param == nil {
+			errs = append(errs, fmt.Errorf("the template %q has no parameter named %q", t.Name, p[0]))
+			continue
+		}
+		param.Value = p[1]
+		param.Generate = ""
+	}
+	for _, param := range t.Parameters {
+		if len(param.Value) == 0 && len(param.Generate) == 0 {
+			errs = append(errs, fmt.Errorf("the template parameter %q requires a value, use --param=%s=<value>", param.Name, param.Name))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.NewAggregate(errs)
+	}
+
+	generators := map[string]generator.Generator{
+		"expression": generator.NewExpressionValueGenerator(rand.New(rand.NewSource(time.Now().UnixNano()))),
+	}
+	config, processErrs := template.NewProcessor(generators).Process(t)
+	if len(processErrs) > 0 {
+		return nil, errors.NewAggregate(processErrs)
+	}
+	if t.ObjectLabels != nil {
+		if labelErrs := template.AddConfigLabels(config, labels.Set(t.ObjectLabels)); len(labelErrs) > 0 {
+			return nil, errors.NewAggregate(labelErrs)
+		}
+	}
+	return &kapi.List{Items: config.Items}, nil
+}
+
+// newTemplate returns a template named name that contains the objects. The value
+// of each container environment variable of a deployment is replaced with a
+// parameter of the same name, since environment variables are the fields
+// substituted when a template is processed.
+func newTemplate(name string, objects []runtime.Object) *templateapi.Template {
+	t := &templateapi.Template{
+		ObjectMeta: kapi.ObjectMeta{Name: name},
+		Objects:    objects,
+	}
+	for _, obj := range objects {
+		switch o := obj.(type) {
+		case *deployapi.DeploymentConfig:
+			if o.Template.ControllerTemplate.Template != nil {
+				parameterizeEnv(t, o.Template.ControllerTemplate.Template.Spec.Containers)
+			}
+		case *deployapi.Deployment:
+			if o.ControllerTemplate.Template != nil {
+				parameterizeEnv(t, o.ControllerTemplate.Template.Spec.Containers)
+			}
+		}
+	}
+	return t
+}
+
+// parameterizeEnv replaces the value of each environment variable of the
+// containers with a reference to a template parameter defaulting to that value.
+// Variables whose names are already parameters with a different value are
+// parameterized with the container name as a prefix.
+func parameterizeEnv(t *templateapi.Template, containers []kapi.Container) {
+	for i := range containers {
+		for j := range containers[i].Env {
+			env := &containers[i].Env[j]
+			name := env.Name
+			if param := template.GetParameterByName(t, name); param != nil && param.Value != env.Value {
+				name = strings.ToUpper(nonParameterChars.ReplaceAllString(containers[i].Name, "_")) + "_" + env.Name
+			}
+			if template.GetParameterByName(t, name) == nil {
+				template.AddParameter(t, templateapi.Parameter{Name: name, Value: env.Value})
+			}
+			env.Value = fmt.Sprintf("${%s}", name)
+		}
+	}
+}
+
+// nonParameterChars matches the characters that may not appear in a parameter name
+var nonParameterChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
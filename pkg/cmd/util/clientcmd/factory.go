@@ -3,6 +3,7 @@ package clientcmd
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
@@ -99,13 +100,22 @@ func NewFactory(clientConfig clientcmd.ClientConfig) *Factory {
 			if err != nil {
 				return nil, fmt.Errorf("unable to describe %s: %v", mapping.Kind, err)
 			}
-			describer, ok := describe.DescriberFor(mapping.Kind, cli, kubeClient, "")
+			describer, ok := describe.DescriberFor(mapping.Kind, cli, kubeClient, cfg.Host)
 			if !ok {
 				return nil, fmt.Errorf("no description has been implemented for %q", mapping.Kind)
 			}
-			return describer, nil
+			configureDescriber(cmd, describer)
+			return withServer(cmd, describer, cfg.Host), nil
 		}
-		return kDescriberFunc(cmd, mapping)
+		describer, err := kDescriberFunc(cmd, mapping)
+		if err != nil || !optionalFlagBool(cmd, "show-server") {
+			return describer, err
+		}
+		cfg, err := w.OpenShiftClientConfig.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("unable to describe %s: %v", mapping.Kind, err)
+		}
+		return withServer(cmd, describer, cfg.Host), nil
 	}
 
 	w.Printer = func(cmd *cobra.Command, mapping *meta.RESTMapping, noHeaders bool) (kubectl.ResourcePrinter, error) {
@@ -115,6 +125,43 @@ func NewFactory(clientConfig clientcmd.ClientConfig) *Factory {
 	return w
 }
 
+// configureDescriber applies the optional describe flags present on cmd to describer.
+func configureDescriber(cmd *cobra.Command, describer kubectl.Describer) {
+	switch d := describer.(type) {
+	case *describe.RouteDescriber:
+		d.CheckConflicts = optionalFlagBool(cmd, "check-conflicts")
+	case *describe.PolicyDescriber:
+		d.MaxRules = optionalFlagInt(cmd, "max-rules")
+	}
+}
+
+// withServer adds the API server to the output of describer if cmd requests it.
+func withServer(cmd *cobra.Command, describer kubectl.Describer, server string) kubectl.Describer {
+	if !optionalFlagBool(cmd, "show-server") {
+		return describer
+	}
+	return &describe.ServerDescriber{Describer: describer, Server: server}
+}
+
+// optionalFlagBool returns the value of a boolean flag, or false if cmd does not define it.
+func optionalFlagBool(cmd *cobra.Command, name string) bool {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil {
+		return false
+	}
+	return flag.Value.String() == "true"
+}
+
+// optionalFlagInt returns the value of an integer flag, or 0 if cmd does not define it.
+func optionalFlagInt(cmd *cobra.Command, name string) int {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil {
+		return 0
+	}
+	value, _ := strconv.Atoi(flag.Value.String())
+	return value
+}
+
 // Clients returns an OpenShift and Kubernetes client.
 func (f *Factory) Clients(cmd *cobra.Command) (*client.Client, *kclient.Client, error) {
 	os, err := f.OpenShiftClientConfig.ClientConfig()
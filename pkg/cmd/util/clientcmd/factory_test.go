@@ -0,0 +1,74 @@
+package clientcmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	clientcmdapi "github.com/GoogleCloudPlatform/kubernetes/pkg/client/clientcmd/api"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/origin/pkg/api/latest"
+	buildapi "github.com/openshift/origin/pkg/build/api"
+)
+
+type fakeClientConfig struct {
+	config *kclient.Config
+}
+
+func (c fakeClientConfig) RawConfig() (clientcmdapi.Config, error) {
+	return clientcmdapi.Config{}, nil
+}
+
+func (c fakeClientConfig) ClientConfig() (*kclient.Config, error) {
+	return c.config, nil
+}
+
+func (c fakeClientConfig) Namespace() (string, error) {
+	return kapi.NamespaceDefault, nil
+}
+
+func TestDescriberWebhookURL(t *testing.T) {
+	config := &buildapi.BuildConfig{
+		ObjectMeta: kapi.ObjectMeta{Name: "app", Namespace: kapi.NamespaceDefault},
+		Triggers: []buildapi.BuildTriggerPolicy{
+			{
+				Type:          buildapi.GithubWebHookBuildTriggerType,
+				GithubWebHook: &buildapi.WebHookTrigger{Secret: "secret101"},
+			},
+		},
+	}
+	data, err := latest.Codec.Encode(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	f := NewFactory(fakeClientConfig{&kclient.Config{Host: server.URL, Version: latest.Version}})
+	mapping, err := latest.RESTMapper.RESTMapping("BuildConfig", latest.Version)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	describer, err := f.Describer(&cobra.Command{}, mapping)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := describer.Describe(kapi.NamespaceDefault, "app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := server.URL + "/osapi/" + latest.Version + "/buildConfigHooks/app/secret101/github"
+	if !strings.Contains(out, expected) {
+		t.Errorf("expected the webhook URL %s in output: %s", expected, out)
+	}
+	if strings.Contains(out, "unavailable") {
+		t.Errorf("unexpected placeholder in output: %s", out)
+	}
+}
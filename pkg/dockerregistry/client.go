@@ -1,9 +1,14 @@
 package dockerregistry
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
 
 	dockerutils "github.com/docker/docker/utils"
@@ -32,9 +37,74 @@ func NewClient() Client {
 	}
 }
 
+// NewClientWithCredentials returns a client object which authenticates to a
+// Docker registry with the credentials in the Docker config file at path. If
+// path is empty, the .dockercfg file in the user's home directory is used if
+// it exists. The certificates of the insecureRegistries are not verified, and
+// those registries may be reached over HTTP.
+func NewClientWithCredentials(path string, insecureRegistries []string) (Client, error) {
+	var credentials *registry.ConfigFile
+	if len(path) == 0 {
+		config, err := registry.LoadConfig(os.Getenv("HOME"))
+		if err != nil {
+			return nil, err
+		}
+		credentials = config
+	} else {
+		config, err := loadCredentialsFile(path)
+		if err != nil {
+			return nil, err
+		}
+		credentials = config
+	}
+	return &client{
+		connections: make(map[string]connection),
+		credentials: credentials,
+		insecure:    insecureRegistries,
+	}, nil
+}
+
 // client implements the Client interface
 type client struct {
 	connections map[string]connection
+	credentials *registry.ConfigFile
+	insecure    []string
+}
+
+// loadCredentialsFile reads registry credentials from a Docker config file,
+// either a .dockercfg file or a config.json file with an "auths" section.
+func loadCredentialsFile(path string) (*registry.ConfigFile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sections := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return nil, fmt.Errorf("unable to read registry credentials from %s: %v", path, err)
+	}
+	if auths, ok := sections["auths"]; ok {
+		data = auths
+	}
+	configs := map[string]registry.AuthConfig{}
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("unable to read registry credentials from %s: %v", path, err)
+	}
+	for server, auth := range configs {
+		if len(auth.Auth) > 0 {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read registry credentials for %s from %s: %v", server, path, err)
+			}
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("unable to read registry credentials for %s from %s: auth must be of the form username:password", server, path)
+			}
+			auth.Username, auth.Password, auth.Auth = parts[0], parts[1], ""
+		}
+		auth.ServerAddress = server
+		configs[server] = auth
+	}
+	return &registry.ConfigFile{Configs: configs}, nil
 }
 
 func (c *client) Connect(name string) (Connection, error) {
@@ -44,11 +114,16 @@ func (c *client) Connect(name string) (Connection, error) {
 	if conn, ok := c.connections[name]; ok {
 		return conn, nil
 	}
-	e, err := registry.NewEndpoint(name, []string{})
+	e, err := registry.NewEndpoint(name, c.insecure)
 	if err != nil {
 		return nil, convertConnectionError(name, err)
 	}
-	session, err := registry.NewSession(&registry.AuthConfig{}, nil, e, true)
+	auth := &registry.AuthConfig{}
+	if c.credentials != nil {
+		resolved := c.credentials.ResolveAuthConfig(name)
+		auth = &resolved
+	}
+	session, err := registry.NewSession(auth, nil, e, true)
 	if err != nil {
 		return nil, convertConnectionError(name, err)
 	}
@@ -171,6 +246,19 @@ func IsNotFound(err error) bool {
 	return IsRegistryNotFound(err) || IsRepositoryNotFound(err) || IsImageNotFound(err) || IsTagNotFound(err)
 }
 
+// IsTemporary returns true if the registry could not be reached or did not respond
+// in time, and the request may succeed if attempted again. Errors returned by the
+// registry, such as authentication failures, are not temporary.
+func IsTemporary(err error) bool {
+	switch t := err.(type) {
+	case *dockerutils.JSONError:
+		return t.Code == http.StatusGatewayTimeout
+	case *url.Error, net.Error:
+		return true
+	}
+	return false
+}
+
 func isDockerNotFoundError(err error) bool {
 	if json, ok := err.(*dockerutils.JSONError); ok && err != nil {
 		return json.Code == http.StatusNotFound
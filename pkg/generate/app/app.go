@@ -136,6 +136,8 @@ func (r *SourceRef) BuildSource() (*buildapi.BuildSource, []buildapi.BuildTrigge
 type BuildStrategyRef struct {
 	IsDockerBuild bool
 	Base          *ImageRef
+	// Platform is the language or platform detected in the source, if any
+	Platform string
 }
 
 // BuildStrategy builds an OpenShift BuildStrategy from a BuildStrategyRef
@@ -1,15 +1,20 @@
 package app
 
 import (
+	"errors"
 	"log"
+	"net/http"
 	"net/url"
 	"testing"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	dockerutils "github.com/docker/docker/utils"
+	"github.com/fsouza/go-dockerclient"
 
 	"github.com/openshift/origin/pkg/api/latest"
 	build "github.com/openshift/origin/pkg/build/api"
+	"github.com/openshift/origin/pkg/dockerregistry"
 	imageapi "github.com/openshift/origin/pkg/image/api"
 )
 
@@ -90,6 +95,28 @@ func TestSimpleDeploymentConfig(t *testing.T) {
 	}
 }
 
+func TestAddServicesMultiplePorts(t *testing.T) {
+	info := testImageInfo()
+	info.Config.ExposedPorts = map[string]struct{}{"8080/tcp": {}, "9090/tcp": {}}
+	image := &ImageRef{Name: "a-long-application-name", Info: info, AsImageRepository: true}
+	deploy := &DeploymentConfigRef{Images: []*ImageRef{image}}
+	config, err := deploy.DeploymentConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	objects := AddServices(Objects{config})
+	if len(objects) != 3 {
+		t.Fatalf("expected a service for each port: %#v", objects)
+	}
+	expected := map[string]int{"a-long-application-name": 8080, "a-long-application-9090": 9090}
+	for _, obj := range objects[:2] {
+		svc := obj.(*kapi.Service)
+		if port, ok := expected[svc.Name]; !ok || svc.Spec.Port != port || svc.Spec.ContainerPort.IntVal != port {
+			t.Errorf("unexpected service: %#v", svc)
+		}
+	}
+}
+
 func TestImageRefDeployableContainerPorts(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -239,3 +266,133 @@ func ExampleGenerateSimpleDockerApp() {
 	log.Print(string(data))
 	// output:
 }
+
+type fakeResolver struct {
+	calls int
+	errs  []error
+}
+
+func (r *fakeResolver) Resolve(value string) (*ComponentMatch, error) {
+	r.calls++
+	if len(r.errs) > 0 {
+		err := r.errs[0]
+		r.errs = r.errs[1:]
+		return nil, err
+	}
+	return &ComponentMatch{Value: value}, nil
+}
+
+func TestRetryResolver(t *testing.T) {
+	temporary := ErrNoMatch{value: "foo", temporary: true}
+	tests := map[string]struct {
+		errs      []error
+		calls     int
+		expectErr bool
+	}{
+		"succeeds": {
+			calls: 1,
+		},
+		"succeeds after temporary failure": {
+			errs:  []error{temporary},
+			calls: 2,
+		},
+		"temporary failures exceed retries": {
+			errs:      []error{temporary, temporary, temporary, temporary},
+			calls:     3,
+			expectErr: true,
+		},
+		"not found is not retried": {
+			errs:      []error{ErrNoMatch{value: "foo"}},
+			calls:     1,
+			expectErr: true,
+		},
+		"multiple matches are not retried": {
+			errs:      []error{ErrMultipleMatches{Image: "foo"}},
+			calls:     1,
+			expectErr: true,
+		},
+		"network errors are retried": {
+			errs:  []error{&url.Error{Op: "Get", URL: "http://registry", Err: errors.New("connection refused")}},
+			calls: 2,
+		},
+	}
+	for name, test := range tests {
+		fake := &fakeResolver{errs: test.errs}
+		resolver := RetryResolver{Resolver: fake, MaxRetries: 2}
+		match, err := resolver.Resolve("foo")
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		} else if err != nil || match == nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		if fake.calls != test.calls {
+			t.Errorf("%s: expected %d calls, got %d", name, test.calls, fake.calls)
+		}
+	}
+}
+
+type fakeRegistryClient struct {
+	connectErr error
+	imageErr   error
+}
+
+func (c fakeRegistryClient) Connect(registry string) (dockerregistry.Connection, error) {
+	if c.connectErr != nil {
+		return nil, c.connectErr
+	}
+	return c, nil
+}
+
+func (c fakeRegistryClient) ImageByTag(namespace, name, tag string) (*docker.Image, error) {
+	return nil, c.imageErr
+}
+
+func TestDockerRegistryResolverRetries(t *testing.T) {
+	tests := map[string]struct {
+		client fakeRegistryClient
+		calls  int
+	}{
+		"unauthorized is not retried": {
+			client: fakeRegistryClient{imageErr: &dockerutils.JSONError{Code: http.StatusUnauthorized, Message: "HTTP code 401"}},
+			calls:  1,
+		},
+		"forbidden is not retried": {
+			client: fakeRegistryClient{imageErr: &dockerutils.JSONError{Code: http.StatusForbidden, Message: "HTTP code 403"}},
+			calls:  1,
+		},
+		"unauthorized on connect is not retried": {
+			client: fakeRegistryClient{connectErr: &dockerutils.JSONError{Code: http.StatusUnauthorized, Message: "HTTP code 401"}},
+			calls:  1,
+		},
+		"gateway timeout is retried": {
+			client: fakeRegistryClient{imageErr: &dockerutils.JSONError{Code: http.StatusGatewayTimeout, Message: "HTTP code 504"}},
+			calls:  3,
+		},
+		"connection failure is retried": {
+			client: fakeRegistryClient{connectErr: &url.Error{Op: "Get", URL: "https://registry", Err: errors.New("i/o timeout")}},
+			calls:  3,
+		},
+	}
+	for name, test := range tests {
+		counter := &countingResolver{Resolver: DockerRegistryResolver{test.client}}
+		resolver := RetryResolver{Resolver: counter, MaxRetries: 2}
+		if _, err := resolver.Resolve("registry.example.com/foo/bar"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		if counter.calls != test.calls {
+			t.Errorf("%s: expected %d calls, got %d", name, test.calls, counter.calls)
+		}
+	}
+}
+
+type countingResolver struct {
+	Resolver
+	calls int
+}
+
+func (r *countingResolver) Resolve(value string) (*ComponentMatch, error) {
+	r.calls++
+	return r.Resolver.Resolve(value)
+}
@@ -2,8 +2,14 @@ package app
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/golang/glog"
 
 	imageapi "github.com/openshift/origin/pkg/image/api"
 	templateapi "github.com/openshift/origin/pkg/template/api"
@@ -193,6 +199,40 @@ func (r WeightedResolvers) Resolve(value string) (*ComponentMatch, error) {
 	}
 }
 
+// RetryResolver retries resolution against the delegate Resolver when it fails
+// with an error that may be temporary, waiting Backoff before the first retry
+// and doubling the wait on each subsequent attempt. Images that are not found
+// and ambiguous matches are returned immediately.
+type RetryResolver struct {
+	Resolver
+	MaxRetries int
+	Backoff    time.Duration
+}
+
+func (r RetryResolver) Resolve(value string) (*ComponentMatch, error) {
+	backoff := r.Backoff
+	for i := 0; ; i++ {
+		match, err := r.Resolver.Resolve(value)
+		if err == nil || i >= r.MaxRetries || !isRetryable(err) {
+			return match, err
+		}
+		glog.V(4).Infof("retrying resolution of %q in %v: %v", value, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isRetryable returns true if the resolver error may succeed when attempted again
+func isRetryable(err error) bool {
+	switch t := err.(type) {
+	case ErrNoMatch:
+		return t.temporary
+	case *url.Error, net.Error:
+		return true
+	}
+	return errors.IsServerTimeout(err)
+}
+
 type ReferenceBuilder struct {
 	refs  ComponentReferences
 	repos []*SourceRepository
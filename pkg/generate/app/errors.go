@@ -8,6 +8,9 @@ import (
 type ErrNoMatch struct {
 	value     string
 	qualifier string
+	// temporary is set when the lookup failed because a remote server
+	// could not be reached, and may succeed if attempted again
+	temporary bool
 }
 
 func (e ErrNoMatch) Error() string {
@@ -133,14 +133,14 @@ func (r DockerRegistryResolver) Resolve(value string) (*ComponentMatch, error) {
 		if dockerregistry.IsRegistryNotFound(err) {
 			return nil, ErrNoMatch{value: value}
 		}
-		return nil, ErrNoMatch{value: value, qualifier: fmt.Sprintf("can't connect to %q: %v", registry, err)}
+		return nil, ErrNoMatch{value: value, qualifier: fmt.Sprintf("can't connect to %q: %v", registry, err), temporary: dockerregistry.IsTemporary(err)}
 	}
 	image, err := connection.ImageByTag(namespace, name, tag)
 	if err != nil {
 		if dockerregistry.IsNotFound(err) {
 			return nil, ErrNoMatch{value: value, qualifier: err.Error()}
 		}
-		return nil, ErrNoMatch{value: value, qualifier: fmt.Sprintf("can't connect to %q: %v", registry, err)}
+		return nil, ErrNoMatch{value: value, qualifier: fmt.Sprintf("can't connect to %q: %v", registry, err), temporary: dockerregistry.IsTemporary(err)}
 	}
 	if len(tag) == 0 {
 		tag = "latest"
@@ -162,6 +162,19 @@ func makeValidServiceName(name string) (string, string) {
 	return name, ""
 }
 
+// portServiceName suffixes a valid service name with a port, shortening the
+// name so that the suffix is kept
+func portServiceName(name, generateName string, port int) (string, string) {
+	suffix := fmt.Sprintf("-%d", port)
+	if len(name) == 0 {
+		return "", generateName + strings.TrimPrefix(suffix, "-") + "-"
+	}
+	if len(name)+len(suffix) > maxServiceNameLength {
+		name = strings.TrimRight(name[:maxServiceNameLength-len(suffix)], "-")
+	}
+	return name + suffix, ""
+}
+
 func sortedPorts(ports []kapi.Port) []int {
 	result := []int{}
 	for _, p := range ports {
@@ -178,8 +191,12 @@ func AddServices(objects Objects) Objects {
 		case *deploy.DeploymentConfig:
 			for _, container := range t.Template.ControllerTemplate.Template.Spec.Containers {
 				ports := sortedPorts(container.Ports)
-				for _, p := range ports {
+				for i, p := range ports {
+					// the first port keeps the name of the deployment config
 					name, generateName := makeValidServiceName(t.Name)
+					if i > 0 {
+						name, generateName = portServiceName(name, generateName, p)
+					}
 					svcs = append(svcs, &kapi.Service{
 						ObjectMeta: kapi.ObjectMeta{
 							Name:         name,
@@ -192,7 +209,6 @@ func AddServices(objects Objects) Objects {
 							Selector:      t.Template.ControllerTemplate.Selector,
 						},
 					})
-					break
 				}
 				break
 			}
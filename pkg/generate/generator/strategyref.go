@@ -5,12 +5,16 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/golang/glog"
 
 	"github.com/openshift/origin/pkg/generate/app"
 	"github.com/openshift/origin/pkg/generate/dockerfile"
 	"github.com/openshift/origin/pkg/generate/errors"
 	"github.com/openshift/origin/pkg/generate/git"
 	"github.com/openshift/origin/pkg/generate/source"
+	imageapi "github.com/openshift/origin/pkg/image/api"
 )
 
 // Flows for BuildStrategyRef
@@ -31,8 +35,14 @@ type BuildStrategyRefGenerator struct {
 
 // NewBuildStrategyRefGenerator creates a BuildStrategyRefGenerator
 func NewBuildStrategyRefGenerator(sourceDetectors source.Detectors, resolver app.Resolver) *BuildStrategyRefGenerator {
+	return NewBuildStrategyRefGeneratorForRepository(git.NewRepository(), sourceDetectors, resolver)
+}
+
+// NewBuildStrategyRefGeneratorForRepository creates a BuildStrategyRefGenerator that uses
+// the given git Repository to retrieve remote source
+func NewBuildStrategyRefGeneratorForRepository(repository git.Repository, sourceDetectors source.Detectors, resolver app.Resolver) *BuildStrategyRefGenerator {
 	return &BuildStrategyRefGenerator{
-		gitRepository:     git.NewRepository(),
+		gitRepository:     repository,
 		dockerfileFinder:  dockerfile.NewFinder(),
 		dockerfileParser:  dockerfile.NewParser(),
 		sourceDetectors:   sourceDetectors,
@@ -41,8 +51,28 @@ func NewBuildStrategyRefGenerator(sourceDetectors source.Detectors, resolver app
 	}
 }
 
+// Build strategies that may be requested for a source repository
+const (
+	DockerStrategy = "docker"
+	SourceStrategy = "source"
+)
+
+// strategyFile is the file, relative to the source root, that may name the
+// build strategy to use for the repository
+const strategyFile = ".openshift/strategy"
+
 // FromSourceRef creates a build strategy from a source reference
 func (g *BuildStrategyRefGenerator) FromSourceRef(srcRef app.SourceRef) (*app.BuildStrategyRef, error) {
+	return g.FromSourceRefAndStrategy(srcRef, "")
+}
+
+// FromSourceRefAndStrategy creates a build strategy from a source reference using
+// the given strategy type. If strategy is empty, the strategy named in the
+// repository's .openshift/strategy file is used, and if that is not present the
+// strategy is detected from the source. The strategy file is always read from the
+// repository root, but when the source reference has a context directory only
+// that directory of the repository is examined for detection.
+func (g *BuildStrategyRefGenerator) FromSourceRefAndStrategy(srcRef app.SourceRef, strategy string) (*app.BuildStrategyRef, error) {
 
 	// Download source locally first if not available
 	if len(srcRef.Dir) == 0 {
@@ -51,25 +81,78 @@ func (g *BuildStrategyRefGenerator) FromSourceRef(srcRef app.SourceRef) (*app.Bu
 		}
 	}
 
-	// Detect a Dockerfile
-	context, found, err := g.detectDockerFile(srcRef.Dir)
-	if err != nil {
-		return nil, err
+	dir := filepath.Join(srcRef.Dir, srcRef.ContextDir)
+	if len(strategy) == 0 {
+		var err error
+		if strategy, err = readStrategyFile(srcRef.Dir); err != nil {
+			return nil, err
+		}
+	}
+	switch strategy {
+	case "", DockerStrategy, SourceStrategy:
+	default:
+		return nil, fmt.Errorf("unknown build strategy %q, must be %q or %q", strategy, DockerStrategy, SourceStrategy)
 	}
-	if found {
-		return g.FromSourceRefAndDockerContext(srcRef, context)
+
+	// Detect a Dockerfile
+	if strategy != SourceStrategy {
+		context, found, err := g.detectDockerFile(dir)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return g.FromSourceRefAndDockerContext(srcRef, filepath.Join(srcRef.ContextDir, context))
+		}
+		if strategy == DockerStrategy {
+			return nil, fmt.Errorf("a %s build was requested but no Dockerfile was found in the source", DockerStrategy)
+		}
 	}
 
 	// Detect a STI repository
-	sourceInfo, ok := g.sourceDetectors.DetectSource(srcRef.Dir)
+	sourceInfo, ok := g.sourceDetectors.DetectSource(dir)
 	if !ok {
 		return nil, errors.CouldNotDetect
 	}
+	for _, warning := range source.Warnings(sourceInfo, dir) {
+		glog.Warningf("%s", warning)
+	}
 	builderImage, err := g.imageForSourceInfo(sourceInfo)
 	if err != nil {
 		return nil, err
 	}
-	return g.FromSTIBuilderImage(builderImage)
+	if procfile, ok := source.ReadProcfile(dir); ok {
+		builderImage = withProcfileHints(builderImage, procfile)
+	}
+	strategyRef, err := g.FromSTIBuilderImage(builderImage)
+	if err != nil {
+		return nil, err
+	}
+	strategyRef.Platform = sourceInfo.Platform
+	return strategyRef, nil
+}
+
+// withProcfileHints returns a copy of the builder image that also exposes the port
+// of the web process declared in the Procfile, if it names one. The ports of the
+// builder image are kept, since the builder does not run the Procfile command and
+// its own process may listen on them.
+func withProcfileHints(image *app.ImageRef, procfile source.Procfile) *app.ImageRef {
+	port, ok := procfile.WebPort()
+	if !ok {
+		return image
+	}
+	glog.V(2).Infof("Also exposing port %s of the Procfile web process", port)
+	updated := *image
+	info := imageapi.DockerImage{}
+	if image.Info != nil {
+		info = *image.Info
+	}
+	exposed := map[string]struct{}{port + "/tcp": {}}
+	for existing := range info.Config.ExposedPorts {
+		exposed[existing] = struct{}{}
+	}
+	info.Config.ExposedPorts = exposed
+	updated.Info = &info
+	return &updated
 }
 
 // FromSourceRefAndDockerContext generates a BuildStrategyRef from a source ref and context path
@@ -137,6 +220,8 @@ func (g *BuildStrategyRefGenerator) imageForSourceInfo(s *source.Info) (*app.Ima
 		imageName = "openshift/wildfly-8-centos"
 	case "NodeJS":
 		imageName = "openshift/nodejs-010-centos7"
+	case "Python":
+		imageName = "openshift/python-33-centos7"
 	default:
 		return nil, errors.NoBuilderFound
 	}
@@ -160,6 +245,24 @@ func (g *BuildStrategyRefGenerator) detectDockerFile(dir string) (contextDir str
 	return "", false, nil
 }
 
+// readStrategyFile returns the build strategy named in the strategy file of the
+// repository root, or an empty string if the file does not exist
+func readStrategyFile(dir string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, strategyFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	strategy := strings.ToLower(strings.TrimSpace(string(data)))
+	switch strategy {
+	case DockerStrategy, SourceStrategy:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("%s must contain %q or %q, not %q", strategyFile, DockerStrategy, SourceStrategy, strategy)
+}
+
 func (g *BuildStrategyRefGenerator) getSource(srcRef *app.SourceRef) error {
 	var err error
 	// Clone git repository into a local directory
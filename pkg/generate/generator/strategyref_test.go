@@ -12,6 +12,7 @@ import (
 	"github.com/openshift/origin/pkg/generate/dockerfile"
 	"github.com/openshift/origin/pkg/generate/generator/test"
 	"github.com/openshift/origin/pkg/generate/source"
+	imageapi "github.com/openshift/origin/pkg/image/api"
 )
 
 var sourceDetectors = source.Detectors{
@@ -161,6 +162,140 @@ func TestFromSourceRefSTI(t *testing.T) {
 	if strategy.IsDockerBuild {
 		t.Errorf("Expected IsDockerBuild to be false")
 	}
+	if strategy.Platform != "JEE" {
+		t.Errorf("Unexpected platform: %s", strategy.Platform)
+	}
+}
+
+func TestFromSourceRefStrategyFile(t *testing.T) {
+	g := &BuildStrategyRefGenerator{
+		gitRepository:     &test.FakeGit{},
+		dockerfileFinder:  &fakeFinder{result: []string{"Dockerfile"}},
+		dockerfileParser:  &fakeParser{dfile{"FROM": []string{"test/parentImage"}}},
+		sourceDetectors:   sourceDetectors,
+		imageRefGenerator: NewImageRefGenerator(),
+	}
+	tmp, err := ioutil.TempDir("", "test")
+	if err != nil {
+		t.Fatalf("Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+	if err := ioutil.WriteFile(filepath.Join(tmp, "Dockerfile"), []byte{}, 0644); err != nil {
+		t.Fatalf("Unable to create temp file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmp, ".openshift"), 0755); err != nil {
+		t.Fatalf("Unable to create temp dir: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmp, ".openshift", "strategy"), []byte("source\n"), 0644); err != nil {
+		t.Fatalf("Unable to create temp file: %v", err)
+	}
+	srcRef := app.SourceRef{Dir: tmp}
+
+	strategy, err := g.FromSourceRef(srcRef)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strategy.IsDockerBuild {
+		t.Errorf("Expected the strategy file to select a source build")
+	}
+
+	strategy, err = g.FromSourceRefAndStrategy(srcRef, DockerStrategy)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strategy.IsDockerBuild {
+		t.Errorf("Expected the requested strategy to override the strategy file")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(tmp, ".openshift", "strategy"), []byte("custom"), 0644); err != nil {
+		t.Fatalf("Unable to create temp file: %v", err)
+	}
+	if _, err := g.FromSourceRef(srcRef); err == nil {
+		t.Errorf("Expected an error for an unknown strategy")
+	}
+}
+
+func TestFromSourceRefStrategyFileWithContextDir(t *testing.T) {
+	g := &BuildStrategyRefGenerator{
+		gitRepository:     &test.FakeGit{},
+		dockerfileFinder:  &fakeFinder{result: []string{"Dockerfile"}},
+		dockerfileParser:  &fakeParser{dfile{"FROM": []string{"test/parentImage"}}},
+		sourceDetectors:   sourceDetectors,
+		imageRefGenerator: NewImageRefGenerator(),
+	}
+	tmp, err := ioutil.TempDir("", "test")
+	if err != nil {
+		t.Fatalf("Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+	if err := os.MkdirAll(filepath.Join(tmp, "web", ".openshift"), 0755); err != nil {
+		t.Fatalf("Unable to create temp dir: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmp, ".openshift"), 0755); err != nil {
+		t.Fatalf("Unable to create temp dir: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmp, "web", "Dockerfile"), []byte{}, 0644); err != nil {
+		t.Fatalf("Unable to create temp file: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmp, ".openshift", "strategy"), []byte("source\n"), 0644); err != nil {
+		t.Fatalf("Unable to create temp file: %v", err)
+	}
+	// a strategy file in the context directory is not used
+	if err := ioutil.WriteFile(filepath.Join(tmp, "web", ".openshift", "strategy"), []byte("docker\n"), 0644); err != nil {
+		t.Fatalf("Unable to create temp file: %v", err)
+	}
+
+	strategy, err := g.FromSourceRef(app.SourceRef{Dir: tmp, ContextDir: "web"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strategy.IsDockerBuild {
+		t.Errorf("Expected the strategy file in the repository root to select a source build")
+	}
+}
+
+func TestFromSourceRefContextDir(t *testing.T) {
+	detected := ""
+	g := &BuildStrategyRefGenerator{
+		gitRepository:    &test.FakeGit{},
+		dockerfileFinder: &fakeFinder{},
+		dockerfileParser: &fakeParser{},
+		sourceDetectors: source.Detectors{func(dir string) (*source.Info, bool) {
+			detected = dir
+			return &source.Info{Platform: "Python"}, true
+		}},
+		imageRefGenerator: NewImageRefGenerator(),
+	}
+	srcRef := app.SourceRef{Dir: "/tmp/dir", ContextDir: "web"}
+	strategy, err := g.FromSourceRef(srcRef)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if detected != filepath.Join("/tmp/dir", "web") {
+		t.Errorf("Expected detection in the context directory, got %s", detected)
+	}
+	if strategy.Base.Name != "python-33-centos7" {
+		t.Errorf("Unexpected base image: %#v", strategy.Base)
+	}
+}
+
+func TestWithProcfileHints(t *testing.T) {
+	image := &app.ImageRef{Info: &imageapi.DockerImage{
+		Config: imageapi.DockerConfig{ExposedPorts: map[string]struct{}{"8080/tcp": {}}},
+	}}
+	updated := withProcfileHints(image, source.Procfile{"web": "bundle exec puma -p 3000"})
+	for _, port := range []string{"8080/tcp", "3000/tcp"} {
+		if _, ok := updated.Info.Config.ExposedPorts[port]; !ok {
+			t.Errorf("Expected port %s to be exposed: %v", port, updated.Info.Config.ExposedPorts)
+		}
+	}
+	if len(image.Info.Config.ExposedPorts) != 1 {
+		t.Errorf("Expected the original image to be unchanged: %v", image.Info.Config.ExposedPorts)
+	}
+
+	if withProcfileHints(image, source.Procfile{"web": "./server --redis redis://cache:6379"}) != image {
+		t.Errorf("Expected the image to be unchanged without a web port")
+	}
 }
 
 type fakeFinder struct {
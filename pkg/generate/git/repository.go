@@ -3,15 +3,18 @@ package git
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"regexp"
 	"strings"
 	"unicode"
 )
 
-// execCmdFunc is a function that executes an external command
-type execCmdFunc func(dir, name string, args ...string) (string, string, error)
+// execCmdFunc is a function that executes an external command with additional
+// environment variables of the form KEY=VALUE
+type execCmdFunc func(dir string, env []string, name string, args ...string) (string, string, error)
 
 // Repository represents a git source repository
 type Repository interface {
@@ -24,6 +27,9 @@ type Repository interface {
 
 type repository struct {
 	exec execCmdFunc
+
+	// httpHeaders are extra HTTP headers ("Name: Value") sent to remote repositories
+	httpHeaders []string
 }
 
 // NewRepository creates a new Repository for the given directory
@@ -33,9 +39,20 @@ func NewRepository() Repository {
 	}
 }
 
+// NewRepositoryWithHTTPHeaders creates a new Repository that sends the given
+// HTTP headers, in the form "Name: Value", when talking to remote repositories.
+// Any git command still running when stop is closed is killed. A nil stop
+// channel never stops commands.
+func NewRepositoryWithHTTPHeaders(headers []string, stop <-chan struct{}) Repository {
+	return &repository{
+		exec:        stoppableExecCmd(stop),
+		httpHeaders: headers,
+	}
+}
+
 // GetRootDir obtains the directory root for a Git repository
 func (r *repository) GetRootDir(location string) (string, error) {
-	dir, _, err := r.exec(location, "git", "rev-parse", "--git-dir")
+	dir, _, err := r.exec(location, nil, "git", "rev-parse", "--git-dir")
 	if err != nil {
 		return "", err
 	}
@@ -61,7 +78,7 @@ var (
 
 // GetOriginURL returns the origin branch URL for the git repository
 func (r *repository) GetOriginURL(location string) (string, bool, error) {
-	text, _, err := r.exec(location, "git", "config", "--get-regexp", "^remote\\..*\\.url$")
+	text, _, err := r.exec(location, nil, "git", "config", "--get-regexp", "^remote\\..*\\.url$")
 	if err != nil {
 		return "", false, err
 	}
@@ -87,7 +104,7 @@ func (r *repository) GetOriginURL(location string) (string, bool, error) {
 
 // GetRef retrieves the current branch reference for the git repository
 func (r *repository) GetRef(location string) string {
-	branch, _, err := r.exec(location, "git", "symbolic-ref", "-q", "--short", "HEAD")
+	branch, _, err := r.exec(location, nil, "git", "symbolic-ref", "-q", "--short", "HEAD")
 	if err != nil {
 		branch = ""
 	}
@@ -96,28 +113,77 @@ func (r *repository) GetRef(location string) string {
 
 // Clone clones a remote git repository to a local directory
 func (r *repository) Clone(location string, url string) error {
-	_, _, err := r.exec("", "git", "clone", "--recursive", url, location)
+	_, _, err := r.exec("", httpHeaderEnv(r.httpHeaders), "git", "clone", "--recursive", url, location)
 	return err
 }
 
+// httpHeaderEnv returns the environment that configures git to send the headers.
+// Headers are passed in the environment rather than as arguments so that they
+// are not visible to other users in the process list. Git 2.31 or newer is
+// required to read configuration from the environment.
+func httpHeaderEnv(headers []string) []string {
+	if len(headers) == 0 {
+		return nil
+	}
+	env := []string{fmt.Sprintf("GIT_CONFIG_COUNT=%d", len(headers))}
+	for i, header := range headers {
+		env = append(env, fmt.Sprintf("GIT_CONFIG_KEY_%d=http.extraHeader", i), fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i, header))
+	}
+	return env
+}
+
 // Checkout switches to the given ref for the git repository
 func (r *repository) Checkout(location string, ref string) error {
-	_, _, err := r.exec(location, "git", "checkout", ref)
+	_, _, err := r.exec(location, nil, "git", "checkout", ref)
 	return err
 }
 
 // execCmd executes an external command in the given directory.
 // The command's standard out and error are trimmed and returned as strings
-func execCmd(dir, name string, args ...string) (stdout, stderr string, err error) {
+func execCmd(dir string, env []string, name string, args ...string) (stdout, stderr string, err error) {
+	return runCmd(nil, dir, env, name, args...)
+}
+
+// ErrStopped is returned when a git command is killed because it was stopped
+var ErrStopped = errors.New("the git command was stopped before it completed")
+
+// stoppableExecCmd returns an execCmdFunc that kills the command if stop is
+// closed before the command completes
+func stoppableExecCmd(stop <-chan struct{}) execCmdFunc {
+	return func(dir string, env []string, name string, args ...string) (string, string, error) {
+		return runCmd(stop, dir, env, name, args...)
+	}
+}
+
+// runCmd executes an external command in the given directory with env added to
+// the current environment, killing it if stop is closed before it completes.
+// The command's standard out and error are trimmed and returned as strings.
+func runCmd(stop <-chan struct{}, dir string, env []string, name string, args ...string) (stdout, stderr string, err error) {
 	cmdOut := &bytes.Buffer{}
 	cmdErr := &bytes.Buffer{}
 
 	cmd := exec.Command(name, args...)
 	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	cmd.Stdout = cmdOut
 	cmd.Stderr = cmdErr
 
-	err = cmd.Run()
+	if err = cmd.Start(); err != nil {
+		return "", "", err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	select {
+	case err = <-done:
+	case <-stop:
+		cmd.Process.Kill()
+		<-done
+		err = ErrStopped
+	}
 	stdout = strings.TrimFunc(cmdOut.String(), unicode.IsSpace)
 	stderr = strings.TrimFunc(cmdErr.String(), unicode.IsSpace)
 	return
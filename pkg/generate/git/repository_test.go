@@ -1,7 +1,11 @@
 package git
 
 import (
+	"os/exec"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetRootDir(t *testing.T) {
@@ -92,6 +96,63 @@ func TestClone(t *testing.T) {
 	}
 }
 
+func TestCloneWithHTTPHeaders(t *testing.T) {
+	tests := []struct {
+		headers []string
+		env     []string
+	}{
+		{
+			headers: nil,
+			env:     nil,
+		},
+		{
+			headers: []string{"X-Auth-Token: abc", "X-Forwarded-User: bob"},
+			env: []string{
+				"GIT_CONFIG_COUNT=2",
+				"GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=X-Auth-Token: abc",
+				"GIT_CONFIG_KEY_1=http.extraHeader", "GIT_CONFIG_VALUE_1=X-Forwarded-User: bob",
+			},
+		},
+	}
+	expectedArgs := []string{"clone", "--recursive", "https://test/url/to/repository", "/test/dir"}
+	for i, test := range tests {
+		r := NewRepositoryWithHTTPHeaders(test.headers, nil).(*repository)
+		var cloneDir, cloneName string
+		var cloneEnv, cloneArgs []string
+		r.exec = func(dir string, env []string, name string, args ...string) (string, string, error) {
+			cloneDir, cloneEnv, cloneName, cloneArgs = dir, env, name, args
+			return "", "", nil
+		}
+		if err := r.Clone("/test/dir", "https://test/url/to/repository"); err != nil {
+			t.Errorf("%d: Unexpected error: %v", i, err)
+		}
+		if cloneDir != "" || cloneName != "git" {
+			t.Errorf("%d: Unexpected command %q in %q", i, cloneName, cloneDir)
+		}
+		// headers must not appear in the arguments, which are visible in the process list
+		if !reflect.DeepEqual(cloneArgs, expectedArgs) {
+			t.Errorf("%d: Unexpected clone arguments: %v. Expected: %v", i, cloneArgs, expectedArgs)
+		}
+		if !reflect.DeepEqual(cloneEnv, test.env) {
+			t.Errorf("%d: Unexpected clone environment: %v. Expected: %v", i, cloneEnv, test.env)
+		}
+	}
+}
+
+func TestHTTPHeaderEnvIsReadByGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	headers := []string{"X-Auth-Token: abc", "X-Forwarded-User: bob"}
+	stdout, _, err := execCmd("", httpHeaderEnv(headers), "git", "config", "--get-all", "http.extraHeader")
+	if err != nil {
+		t.Skipf("git does not read configuration from the environment: %v", err)
+	}
+	if stdout != strings.Join(headers, "\n") {
+		t.Errorf("Unexpected headers read by git: %q", stdout)
+	}
+}
+
 func TestCheckout(t *testing.T) {
 	r := &repository{exec: makeExecFunc("", nil)}
 	err := r.Checkout("/test/dir", "branch2")
@@ -100,8 +161,46 @@ func TestCheckout(t *testing.T) {
 	}
 }
 
+func TestStoppableExecCmd(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep is not available")
+	}
+	stop := make(chan struct{})
+	result := make(chan error, 1)
+	start := time.Now()
+	go func() {
+		_, _, err := stoppableExecCmd(stop)("", nil, "sleep", "30")
+		result <- err
+	}()
+	close(stop)
+	select {
+	case err := <-result:
+		if err != ErrStopped {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 10*time.Second {
+			t.Errorf("Expected the command to be killed, it ran for %v", elapsed)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Expected the command to be killed when stopped")
+	}
+}
+
+func TestStoppableExecCmdCompletes(t *testing.T) {
+	if _, err := exec.LookPath("echo"); err != nil {
+		t.Skip("echo is not available")
+	}
+	stdout, _, err := stoppableExecCmd(make(chan struct{}))("", nil, "echo", "done")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if stdout != "done" {
+		t.Errorf("Unexpected output: %s", stdout)
+	}
+}
+
 func makeExecFunc(output string, err error) execCmdFunc {
-	return func(dir, name string, args ...string) (out string, errout string, resultErr error) {
+	return func(dir string, env []string, name string, args ...string) (out string, errout string, resultErr error) {
 		out = output
 		resultErr = err
 		return
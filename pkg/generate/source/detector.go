@@ -1,8 +1,10 @@
 package source
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // Info is detected platform information from a source directory
@@ -24,6 +26,7 @@ var DefaultDetectors = Detectors{
 	DetectRuby,
 	DetectJava,
 	DetectNodeJS,
+	DetectPython,
 }
 
 type sourceDetector struct {
@@ -71,6 +74,34 @@ func DetectNodeJS(dir string) (*Info, bool) {
 	return nil, false
 }
 
+// DetectPython detects whether the source code in the given repository is Python
+func DetectPython(dir string) (*Info, bool) {
+	if filesPresent(dir, []string{"requirements.txt", "setup.py"}) || globPresent(dir, "*.py") {
+		return &Info{
+			Platform: "Python",
+		}, true
+	}
+	return nil, false
+}
+
+// expectedFiles are the files the default builder of a platform expects to find
+// in the source, at least one of which should be present
+var expectedFiles = map[string][]string{
+	"Ruby":   {"Gemfile"},
+	"NodeJS": {"package.json"},
+	"Python": {"requirements.txt", "setup.py"},
+}
+
+// Warnings returns a warning for each file the builder of the detected platform
+// expects that is missing from the source in the given directory
+func Warnings(info *Info, dir string) []string {
+	files, ok := expectedFiles[info.Platform]
+	if !ok || filesPresent(dir, files) {
+		return nil
+	}
+	return []string{fmt.Sprintf("the source was detected as %s but has no %s, the build may fail", info.Platform, strings.Join(files, " or "))}
+}
+
 func filesPresent(dir string, files []string) bool {
 	for _, f := range files {
 		_, err := os.Stat(filepath.Join(dir, f))
@@ -80,3 +111,8 @@ func filesPresent(dir string, files []string) bool {
 	}
 	return false
 }
+
+func globPresent(dir, pattern string) bool {
+	matches, err := filepath.Glob(filepath.Join(dir, pattern))
+	return err == nil && len(matches) > 0
+}
@@ -1,6 +1,9 @@
 package source
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -26,6 +29,30 @@ func TestDetectSource(t *testing.T) {
 	}
 }
 
+func TestDetectPython(t *testing.T) {
+	for _, file := range []string{"requirements.txt", "setup.py", "app.py"} {
+		dir, err := ioutil.TempDir("", "python")
+		if err != nil {
+			t.Fatalf("Unable to create temp dir: %v", err)
+		}
+		defer os.RemoveAll(dir)
+		if _, ok := DetectPython(dir); ok {
+			t.Errorf("Detected Python in an empty directory")
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, file), []byte{}, 0644); err != nil {
+			t.Fatalf("Unable to create temp file: %v", err)
+		}
+		i, ok := DefaultDetectors.DetectSource(dir)
+		if !ok {
+			t.Errorf("Unable to detect source with %s", file)
+			continue
+		}
+		if i.Platform != "Python" {
+			t.Errorf("Invalid platform for %s: %s", file, i.Platform)
+		}
+	}
+}
+
 func fake1(dir string) (*Info, bool) {
 	if strings.Contains(dir, "fake1") {
 		return &Info{
@@ -44,3 +71,38 @@ func fake2(dir string) (*Info, bool) {
 	return nil, false
 
 }
+
+func TestWarnings(t *testing.T) {
+	tests := map[string]struct {
+		files    []string
+		platform string
+		warn     bool
+	}{
+		"ruby with Gemfile":       {files: []string{"Gemfile", "Rakefile"}, platform: "Ruby"},
+		"ruby without Gemfile":    {files: []string{"Rakefile"}, platform: "Ruby", warn: true},
+		"node without package":    {files: []string{"config.json"}, platform: "NodeJS", warn: true},
+		"python with setup.py":    {files: []string{"setup.py"}, platform: "Python"},
+		"python with source only": {files: []string{"app.py"}, platform: "Python", warn: true},
+		"java":                    {files: []string{"pom.xml"}, platform: "JEE"},
+	}
+	for name, test := range tests {
+		dir, err := ioutil.TempDir("", "warnings")
+		if err != nil {
+			t.Fatalf("Unable to create temp dir: %v", err)
+		}
+		defer os.RemoveAll(dir)
+		for _, file := range test.files {
+			if err := ioutil.WriteFile(filepath.Join(dir, file), []byte{}, 0644); err != nil {
+				t.Fatalf("Unable to create temp file: %v", err)
+			}
+		}
+		i, ok := DefaultDetectors.DetectSource(dir)
+		if !ok || i.Platform != test.platform {
+			t.Errorf("%s: unexpected detection: %#v", name, i)
+			continue
+		}
+		if warnings := Warnings(i, dir); (len(warnings) > 0) != test.warn {
+			t.Errorf("%s: unexpected warnings: %v", name, warnings)
+		}
+	}
+}
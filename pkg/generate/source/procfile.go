@@ -0,0 +1,67 @@
+package source
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Procfile maps the process types declared in a Heroku style Procfile to
+// their commands
+type Procfile map[string]string
+
+// procfilePort matches a port passed to a process command explicitly, with a
+// --port or -p flag or a PORT environment variable. Other host:port values, such
+// as the addresses of services the process connects to, are not matched.
+var procfilePort = regexp.MustCompile(`(?:^|\s)(?:--port[= ]|-p ?|PORT=)([0-9]{2,5})\b`)
+
+// ReadProcfile reads the Procfile in the given directory. It returns false if
+// the file is absent or declares no processes.
+func ReadProcfile(dir string) (Procfile, bool) {
+	file, err := os.Open(filepath.Join(dir, "Procfile"))
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	procfile := Procfile{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		name := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || len(name) == 0 || strings.ContainsAny(name, " \t") {
+			continue
+		}
+		procfile[name] = strings.TrimSpace(parts[1])
+	}
+	if scanner.Err() != nil || len(procfile) == 0 {
+		return nil, false
+	}
+	return procfile, true
+}
+
+// webCommand returns the command of the web process
+func (p Procfile) webCommand() (string, bool) {
+	command, ok := p["web"]
+	return command, ok && len(command) > 0
+}
+
+// WebPort returns the port the web process command listens on, if the
+// command names one
+func (p Procfile) WebPort() (string, bool) {
+	command, ok := p.webCommand()
+	if !ok {
+		return "", false
+	}
+	matches := procfilePort.FindStringSubmatch(command)
+	if len(matches) != 2 {
+		return "", false
+	}
+	return matches[1], true
+}
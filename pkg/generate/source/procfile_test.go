@@ -0,0 +1,100 @@
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadProcfile(t *testing.T) {
+	tests := map[string]struct {
+		contents string
+		found    bool
+		command  string
+		port     string
+	}{
+		"rails": {
+			contents: "web: bundle exec rails server -p 3000\nworker: bundle exec rake jobs:work\n",
+			found:    true,
+			command:  "bundle exec rails server -p 3000",
+			port:     "3000",
+		},
+		"port flag": {
+			contents: "web: thin start --port 8080\n",
+			found:    true,
+			command:  "thin start --port 8080",
+			port:     "8080",
+		},
+		"port variable": {
+			contents: "web: PORT=5000 node server.js\n",
+			found:    true,
+			command:  "PORT=5000 node server.js",
+			port:     "5000",
+		},
+		"bind address is not a port flag": {
+			contents: "# processes\nweb: gunicorn app:app -b 0.0.0.0:8080\n",
+			found:    true,
+			command:  "gunicorn app:app -b 0.0.0.0:8080",
+		},
+		"url with a port": {
+			contents: "web: ./server --redis redis://cache:6379/0\n",
+			found:    true,
+			command:  "./server --redis redis://cache:6379/0",
+		},
+		"url with a port before a port flag": {
+			contents: "web: ./server --db postgres://db:5432/app -p 3000\n",
+			found:    true,
+			command:  "./server --db postgres://db:5432/app -p 3000",
+			port:     "3000",
+		},
+		"colon argument": {
+			contents: "web: ./server -t :30\n",
+			found:    true,
+			command:  "./server -t :30",
+		},
+		"flag ending in p": {
+			contents: "web: ./server --no-sleep-p 80\n",
+			found:    true,
+			command:  "./server --no-sleep-p 80",
+		},
+		"port from environment": {
+			contents: "web: node server.js --port=$PORT\n",
+			found:    true,
+			command:  "node server.js --port=$PORT",
+		},
+		"no web process": {
+			contents: "worker: ./run-worker\n",
+			found:    true,
+		},
+		"unparseable": {
+			contents: "this is not a procfile\n",
+		},
+	}
+	for name, test := range tests {
+		dir, err := ioutil.TempDir("", "procfile")
+		if err != nil {
+			t.Fatalf("unable to create temp dir: %v", err)
+		}
+		defer os.RemoveAll(dir)
+		if err := ioutil.WriteFile(filepath.Join(dir, "Procfile"), []byte(test.contents), 0644); err != nil {
+			t.Fatalf("unable to write Procfile: %v", err)
+		}
+
+		procfile, found := ReadProcfile(dir)
+		if found != test.found {
+			t.Errorf("%s: expected found to be %t", name, test.found)
+			continue
+		}
+		if command, _ := procfile.webCommand(); command != test.command {
+			t.Errorf("%s: unexpected web command %q", name, command)
+		}
+		if port, _ := procfile.WebPort(); port != test.port {
+			t.Errorf("%s: unexpected web port %q", name, port)
+		}
+	}
+
+	if _, found := ReadProcfile("/non/existent/dir"); found {
+		t.Errorf("expected no Procfile in a missing directory")
+	}
+}
@@ -1,13 +1,32 @@
 package validation
 
 import (
+	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/openshift/origin/pkg/project/api"
 )
 
+// displayNameMaxLength is the maximum number of characters in a project display name
+const displayNameMaxLength = 255
+
+// MinProjectNameLength is the minimum number of characters in a project name
+var MinProjectNameLength = 2
+
+// ReservedProjectNames are names that may not be used for a project because
+// they are used by the system
+var ReservedProjectNames = util.NewStringSet("default", "openshift", "kube-system")
+
+// descriptionAnnotation is the annotation holding the description of a project
+const descriptionAnnotation = "description"
+
+// descriptionMaxLength is the maximum number of characters in a project description
+const descriptionMaxLength = 2048
+
 // ValidateProject tests required fields for a Project.
 func ValidateProject(project *api.Project) errors.ValidationErrorList {
 	result := errors.ValidationErrorList{}
@@ -15,6 +34,10 @@ func ValidateProject(project *api.Project) errors.ValidationErrorList {
 		result = append(result, errors.NewFieldRequired("name", project.Name))
 	} else if !util.IsDNSSubdomain(project.Name) {
 		result = append(result, errors.NewFieldInvalid("name", project.Name, "does not conform to lower-cased dns1123"))
+	} else if len(project.Name) < MinProjectNameLength {
+		result = append(result, errors.NewFieldInvalid("name", project.Name, fmt.Sprintf("must be at least %d characters long", MinProjectNameLength)))
+	} else if ReservedProjectNames.Has(project.Name) {
+		result = append(result, errors.NewFieldInvalid("name", project.Name, "is reserved"))
 	}
 	if len(project.Namespace) > 0 {
 		result = append(result, errors.NewFieldInvalid("namespace", project.Namespace, "must be the empty-string"))
@@ -22,9 +45,31 @@ func ValidateProject(project *api.Project) errors.ValidationErrorList {
 	if !validateNoNewLineOrTab(project.DisplayName) {
 		result = append(result, errors.NewFieldInvalid("displayName", project.DisplayName, "may not contain a new line or tab"))
 	}
+	if utf8.RuneCountInString(project.DisplayName) > displayNameMaxLength {
+		result = append(result, errors.NewFieldInvalid("displayName", project.DisplayName, fmt.Sprintf("may not be longer than %d characters", displayNameMaxLength)))
+	}
+	if description, ok := project.Annotations[descriptionAnnotation]; ok {
+		field := "annotations." + descriptionAnnotation
+		if !validateNoControlCharacters(description) {
+			result = append(result, errors.NewFieldInvalid(field, description, "may not contain control characters other than new lines"))
+		}
+		if utf8.RuneCountInString(description) > descriptionMaxLength {
+			result = append(result, errors.NewFieldInvalid(field, description, fmt.Sprintf("may not be longer than %d characters", descriptionMaxLength)))
+		}
+	}
 	return result
 }
 
+// validateNoControlCharacters ensures a string has no control characters other than new-line
+func validateNoControlCharacters(s string) bool {
+	for _, r := range s {
+		if r != '\n' && unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
 // validateNoNewLineOrTab ensures a string has no new-line or tab
 func validateNoNewLineOrTab(s string) bool {
 	return !(strings.Contains(s, "\n") || strings.Contains(s, "\t"))
@@ -1,9 +1,11 @@
 package validation
 
 import (
+	"strings"
 	"testing"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/openshift/origin/pkg/project/api"
 )
 
@@ -53,11 +55,20 @@ func TestValidateProject(t *testing.T) {
 			name: "valid id leading number",
 			project: api.Project{
 				ObjectMeta: kapi.ObjectMeta{
-					Name: "1",
+					Name: "1a",
 				},
 			},
 			numErrs: 0,
 		},
+		{
+			name: "id too short",
+			project: api.Project{
+				ObjectMeta: kapi.ObjectMeta{
+					Name: "a",
+				},
+			},
+			numErrs: 1,
+		},
 		{
 			name: "valid id internal dots",
 			project: api.Project{
@@ -85,6 +96,66 @@ func TestValidateProject(t *testing.T) {
 			// Should fail because the display name has \t \n
 			numErrs: 1,
 		},
+		{
+			name: "display name at maximum length",
+			project: api.Project{
+				ObjectMeta:  kapi.ObjectMeta{Name: "foo"},
+				DisplayName: strings.Repeat("é", displayNameMaxLength),
+			},
+			// Multibyte characters count once toward the limit
+			numErrs: 0,
+		},
+		{
+			name: "display name too long",
+			project: api.Project{
+				ObjectMeta:  kapi.ObjectMeta{Name: "foo"},
+				DisplayName: strings.Repeat("a", displayNameMaxLength+1),
+			},
+			// Should fail because the display name is longer than the limit
+			numErrs: 1,
+		},
+		{
+			name: "empty description",
+			project: api.Project{
+				ObjectMeta: kapi.ObjectMeta{
+					Name:        "foo",
+					Annotations: map[string]string{"description": ""},
+				},
+			},
+			numErrs: 0,
+		},
+		{
+			name: "multiline description",
+			project: api.Project{
+				ObjectMeta: kapi.ObjectMeta{
+					Name:        "foo",
+					Annotations: map[string]string{"description": "first line\nsecond line"},
+				},
+			},
+			numErrs: 0,
+		},
+		{
+			name: "description with control characters",
+			project: api.Project{
+				ObjectMeta: kapi.ObjectMeta{
+					Name:        "foo",
+					Annotations: map[string]string{"description": "tab\tand\x1b escape"},
+				},
+			},
+			// Should fail because the description has \t and an escape character
+			numErrs: 1,
+		},
+		{
+			name: "description too long",
+			project: api.Project{
+				ObjectMeta: kapi.ObjectMeta{
+					Name:        "foo",
+					Annotations: map[string]string{"description": strings.Repeat("a", descriptionMaxLength+1)},
+				},
+			},
+			// Should fail because the description is longer than the limit
+			numErrs: 1,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -108,3 +179,58 @@ func TestValidateProject(t *testing.T) {
 		t.Errorf("Unexpected non-zero error list: %#v", errs)
 	}
 }
+
+func TestValidateProjectReservedNames(t *testing.T) {
+	for _, name := range []string{"default", "openshift", "kube-system"} {
+		project := api.Project{ObjectMeta: kapi.ObjectMeta{Name: name}}
+		if errs := ValidateProject(&project); len(errs) != 1 {
+			t.Errorf("Expected the reserved name %q to be rejected: %#v", name, errs)
+		}
+	}
+	for _, name := range []string{"defaults", "openshift-apps", "kube"} {
+		project := api.Project{ObjectMeta: kapi.ObjectMeta{Name: name}}
+		if errs := ValidateProject(&project); len(errs) != 0 {
+			t.Errorf("Unexpected error list for allowed name %q: %#v", name, errs)
+		}
+	}
+
+	reserved := ReservedProjectNames
+	defer func() { ReservedProjectNames = reserved }()
+	ReservedProjectNames = util.NewStringSet("foo")
+	project := api.Project{ObjectMeta: kapi.ObjectMeta{Name: "foo"}}
+	if errs := ValidateProject(&project); len(errs) != 1 {
+		t.Errorf("Expected the configured reserved name to be rejected: %#v", errs)
+	}
+	project.Name = "default"
+	if errs := ValidateProject(&project); len(errs) != 0 {
+		t.Errorf("Unexpected error list after changing the reserved names: %#v", errs)
+	}
+}
+
+func TestValidateProjectMinNameLength(t *testing.T) {
+	tests := map[string]int{
+		"a":   1,
+		"ab":  0,
+		"abc": 0,
+	}
+	for name, numErrs := range tests {
+		project := api.Project{ObjectMeta: kapi.ObjectMeta{Name: name}}
+		if errs := ValidateProject(&project); len(errs) != numErrs {
+			t.Errorf("Unexpected error list for name %q: %#v", name, errs)
+		}
+	}
+
+	minLength := MinProjectNameLength
+	defer func() { MinProjectNameLength = minLength }()
+	MinProjectNameLength = 3
+	tests = map[string]int{
+		"ab":  1,
+		"abc": 0,
+	}
+	for name, numErrs := range tests {
+		project := api.Project{ObjectMeta: kapi.ObjectMeta{Name: name}}
+		if errs := ValidateProject(&project); len(errs) != numErrs {
+			t.Errorf("Unexpected error list for name %q with a minimum length of 3: %#v", name, errs)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package template
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	errs "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	configapi "github.com/openshift/origin/pkg/config/api"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	"github.com/openshift/origin/pkg/template/api"
@@ -83,6 +85,25 @@ func GetParameterByName(t *api.Template, name string) *api.Parameter {
 	return nil
 }
 
+// ParameterReferences returns the names of the parameters referenced by
+// parameter expressions in any field of the given object.
+func ParameterReferences(obj runtime.Object) (util.StringSet, error) {
+	data := []byte{}
+	if unknown, ok := obj.(*runtime.Unknown); ok {
+		data = unknown.RawJSON
+	} else {
+		var err error
+		if data, err = json.Marshal(obj); err != nil {
+			return nil, err
+		}
+	}
+	names := util.NewStringSet()
+	for _, match := range parameterExp.FindAllSubmatch(data, -1) {
+		names.Insert(string(match[1]))
+	}
+	return names, nil
+}
+
 // SubstituteParameters loops over all Environment variables defined for
 // all ReplicationController and Pod containers and substitutes all
 // Parameter expression occurrences with their corresponding values.